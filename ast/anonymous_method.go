@@ -0,0 +1,35 @@
+package ast
+
+import "github.com/akm/tparser/ast/astcore"
+
+// - AnonymousMethod
+//   ```
+//   PROCEDURE [FormalParameters] Block
+//   ```
+//   ```
+//   FUNCTION [FormalParameters] ':' TypeId Block
+//   ```
+type AnonymousMethod struct {
+	FunctionType     FunctionType
+	FormalParameters FormalParameters
+	ReturnType       *TypeId
+	Block            *Block
+	// Captures are the local variables, parameters or Self of the enclosing
+	// routines which are referred in the anonymous method.
+	Captures astcore.Decls
+}
+
+var _ Factor = (*AnonymousMethod)(nil)
+
+func (*AnonymousMethod) isFactor() {}
+func (m *AnonymousMethod) Children() Nodes {
+	r := Nodes{}
+	if m.FormalParameters != nil {
+		r = append(r, m.FormalParameters)
+	}
+	if m.ReturnType != nil {
+		r = append(r, m.ReturnType)
+	}
+	r = append(r, m.Block)
+	return r
+}
@@ -18,7 +18,7 @@ type Location struct {
 }
 
 func NewLocation(start, end *runes.Position) *Location {
-	return &Location{Start: start, End: end}
+	return &Location{Path: start.Path, Start: start, End: end}
 }
 
 func (m *Location) String() string {
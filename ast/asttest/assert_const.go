@@ -29,6 +29,7 @@ func AssertConstantDecl(t *testing.T, expected, actual *ast.ConstantDecl) {
 	if !assert.Equal(t, expected.ConstExpr, actual.ConstExpr) {
 		AssertConstExpr(t, expected.ConstExpr, actual.ConstExpr)
 	}
+	assert.Equal(t, expected.TypedConstant, actual.TypedConstant)
 	if !assert.Equal(t, expected.PortabilityDirective, actual.PortabilityDirective) {
 		AssertPortabilityDirective(t, expected.PortabilityDirective, actual.PortabilityDirective)
 	}
@@ -178,8 +178,32 @@ func ClearLocations(t *testing.T, node ast.Node) {
 		switch v := n.(type) {
 		case *ast.Ident:
 			ClearLocation(v)
+		case *ast.Unit:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.Program:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.Library:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.Package:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.Statement:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.FunctionDecl:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.TypeDecl:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.ConstantDecl:
+			clearDirectiveLocations(v.CompilerDirectives)
+		case *ast.VarDecl:
+			clearDirectiveLocations(v.CompilerDirectives)
 		}
 		return nil
 	})
 	assert.NoError(t, err)
 }
+
+func clearDirectiveLocations(directives ast.CompilerDirectives) {
+	for _, d := range directives {
+		d.Location = nil
+	}
+}
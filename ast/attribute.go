@@ -0,0 +1,38 @@
+package ast
+
+// - Attributes
+//   ```
+//   '[' Attribute ','... ']' ...
+//   ```
+type Attributes []*Attribute
+
+var _ Node = (Attributes)(nil)
+
+func (s Attributes) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - Attribute
+//   ```
+//   Ident ['(' [ExprList] ')']
+//   ```
+// Ref of IdentRef refers the attribute class. The class named Ident with the
+// suffix "Attribute" is preferred to the class named Ident.
+type Attribute struct {
+	*IdentRef
+	ExprList ExprList
+}
+
+var _ Node = (*Attribute)(nil)
+
+func (m *Attribute) Children() Nodes {
+	r := Nodes{m.IdentRef}
+	if m.ExprList != nil {
+		r = append(r, m.ExprList)
+	}
+	return r
+}
@@ -80,11 +80,13 @@ func (m *ExportsStmt) Children() Nodes {
 //   ```
 //   Ident [NAME|INDEX “‘” ConstExpr “‘”]
 //         [INDEX|NAME “‘” ConstExpr “‘”]
+//         [RESIDENT]
 //   ```
 type ExportsItem struct {
 	*Ident
-	Name  *ConstExpr
-	Index *ConstExpr
+	Name     *ConstExpr
+	Index    *ConstExpr
+	Resident bool
 }
 
 var _ Node = (*ExportsItem)(nil)
@@ -0,0 +1,48 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/token"
+)
+
+// CompilerDirective is a compiler directive such as {$R *.dfm}, {$H+} or
+// {$WARN SYMBOL_DEPRECATED OFF} written in a source file.
+type CompilerDirective struct {
+	Name     string   // upper case name such as "R", "H" or "WARN"
+	Argument string   // the rest of the directive such as "*.dfm"
+	Args     []string // Argument split by spaces and commas
+	Location *astcore.Location
+}
+
+// NewCompilerDirective returns the CompilerDirective for the CompilerDirective token.
+func NewCompilerDirective(t *token.Token) *CompilerDirective {
+	res := &CompilerDirective{Location: astcore.NewLocation(t.Start, t.End)}
+	if t.CompilerDirective != nil {
+		res.Name = t.CompilerDirective.Name
+		res.Argument = t.CompilerDirective.Argument
+		res.Args = t.CompilerDirective.Args
+	}
+	return res
+}
+
+// CompilerDirectives are the compiler directives in a unit, a program, a library
+// or a package in the order of their positions. The ones in inactive blocks of
+// conditional compilation are not included.
+//
+// Statements and declarations also have the compiler directives just before
+// them. They are the same instances as the ones of the unit, the program, the
+// library or the package.
+type CompilerDirectives []*CompilerDirective
+
+// ByName returns the compiler directives named name such as "R" or "WARN".
+func (s CompilerDirectives) ByName(name string) CompilerDirectives {
+	var r CompilerDirectives
+	for _, d := range s {
+		if strings.EqualFold(d.Name, name) {
+			r = append(r, d)
+		}
+	}
+	return r
+}
@@ -10,9 +10,11 @@ type ConstSection []*ConstantDecl // must implement InterfaceDecl
 
 var _ InterfaceDecl = (ConstSection)(nil)
 var _ DeclSection = (ConstSection)(nil)
+var _ NestedDeclSection = (ConstSection)(nil)
 
-func (ConstSection) canBeInterfaceDecl() {}
-func (ConstSection) canBeDeclSection()   {}
+func (ConstSection) canBeInterfaceDecl()     {}
+func (ConstSection) canBeDeclSection()       {}
+func (ConstSection) canBeNestedDeclSection() {}
 func (s ConstSection) Children() Nodes {
 	r := make(Nodes, len(s))
 	for i, m := range s {
@@ -37,9 +39,12 @@ func (s ConstSection) GetDeclNodes() astcore.DeclNodes {
 //   ```
 type ConstantDecl struct {
 	*Ident
-	Type                 Type
-	ConstExpr            *ConstExpr
+	Type      Type
+	ConstExpr *ConstExpr
+	// TypedConstant is set instead of ConstExpr when the value is an ArrayConstant or a RecordConstant.
+	TypedConstant        TypedConstant
 	PortabilityDirective *PortabilityDirective
+	CompilerDirectives   CompilerDirectives // the compiler directives just before the declaration
 }
 
 var _ astcore.DeclNode = (*ConstantDecl)(nil)
@@ -49,7 +54,12 @@ func (m *ConstantDecl) Children() Nodes {
 	if m.Type != nil {
 		r = append(r, m.Type)
 	}
-	r = append(r, m.ConstExpr)
+	if m.ConstExpr != nil {
+		r = append(r, m.ConstExpr)
+	}
+	if m.TypedConstant != nil {
+		r = append(r, m.TypedConstant)
+	}
 	return r
 }
 
@@ -68,3 +78,65 @@ func NewConstExpr(arg interface{}) *ConstExpr {
 }
 
 type ConstExprs = ExprList
+
+// - TypedConstant
+//   ```
+//   (ConstExpr | ArrayConstant | RecordConstant)
+//   ```
+type TypedConstant interface {
+	Node
+	isTypedConstant()
+}
+
+var _ TypedConstant = (*ConstExpr)(nil)
+
+func (*Expression) isTypedConstant() {}
+
+// - ArrayConstant
+//   ```
+//   '(' TypedConstant ','... ')'
+//   ```
+type ArrayConstant []TypedConstant
+
+var _ TypedConstant = (ArrayConstant)(nil)
+
+func (ArrayConstant) isTypedConstant() {}
+func (s ArrayConstant) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - RecordConstant
+//   ```
+//   '(' RecordFieldConstant ';'... ')'
+//   ```
+type RecordConstant []*RecordFieldConstant
+
+var _ TypedConstant = (RecordConstant)(nil)
+
+func (RecordConstant) isTypedConstant() {}
+func (s RecordConstant) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - RecordFieldConstant
+//   ```
+//   Ident ':' TypedConstant
+//   ```
+type RecordFieldConstant struct {
+	*IdentRef // refers the FieldDecl if the record type is known
+	Value     TypedConstant
+}
+
+var _ Node = (*RecordFieldConstant)(nil)
+
+func (m *RecordFieldConstant) Children() Nodes {
+	return Nodes{m.IdentRef, m.Value}
+}
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
 	"github.com/pkg/errors"
 )
@@ -269,6 +270,9 @@ func (s MulOpFactors) Children() Nodes {
 //   ```
 //   TypeId '(' Expression ')'
 //   ```
+//   ```
+//   AnonymousMethod
+//   ```
 type Factor interface {
 	Node
 	isFactor()
@@ -314,7 +318,7 @@ func (*Address) isFactor()         {}
 
 // - Designator
 //   ```
-//   QualId ['.' Ident | '[' ExprList ']' | '^']...
+//   QualId ['.' Ident | '[' ExprList ']' | '^' | TypeArgs]...
 //   ```
 type Designator struct {
 	*QualId
@@ -373,6 +377,9 @@ type DesignatorItem interface {
 
 type DesignatorItemIdent struct {
 	*Ident
+	// Ref is the declaration of the member which is found in the type of the
+	// preceding part or in the helpers for the type.
+	Ref *astcore.Decl
 }
 
 var _ DesignatorItem = (*DesignatorItemIdent)(nil)
@@ -414,32 +421,53 @@ var _ DesignatorItem = (*DesignatorItemDereference)(nil)
 func (*DesignatorItemDereference) Children() Nodes   { return Nodes{} }
 func (*DesignatorItemDereference) isDesignatorItem() {}
 
+// DesignatorItemTypeArgs is used for specialization such as TList<Integer>.Create or Obj.Method<Integer>(X).
+type DesignatorItemTypeArgs TypeArgs
+
+var _ DesignatorItem = (DesignatorItemTypeArgs)(nil)
+
+func (DesignatorItemTypeArgs) isDesignatorItem() {}
+func (s DesignatorItemTypeArgs) Children() Nodes {
+	return TypeArgs(s).Children()
+}
+
 //   ```
 //   Number
 //   ```
 
 type NumberFactor struct {
-	Value string
+	Value string  // the numeral as written in the source
+	Radix int     // 16 for $FF, 2 for %1010 and 10 for the others
+	Int   int64   // the value of integer numerals. 0 for real numerals
+	Float float64 // the value as a real number
 }
 
 var _ Factor = (*NumberFactor)(nil)
 
-func NewNumber(v string) *NumberFactor { return &NumberFactor{Value: v} }
-func (*NumberFactor) Children() Nodes  { return Nodes{} }
-func (*NumberFactor) isFactor()        {}
+// NewNumber returns NumberFactor with the value of v.
+// The value is zero if v is not a valid numeral.
+func NewNumber(v string) *NumberFactor {
+	radix, i, f, _ := token.ParseNumeral(v)
+	return &NumberFactor{Value: v, Radix: radix, Int: i, Float: f}
+}
+func (*NumberFactor) Children() Nodes { return Nodes{} }
+func (*NumberFactor) isFactor()       {}
 
 //   ```
 //   String
 //   ```
 type StringFactor struct {
-	Value string
+	Value   string // the character string as written in the source
+	Decoded string // the text which the character string represents
 }
 
 var _ Factor = (*StringFactor)(nil)
 
-func NewString(v string) *StringFactor { return &StringFactor{Value: v} }
-func (*StringFactor) Children() Nodes  { return Nodes{} }
-func (*StringFactor) isFactor()        {}
+func NewString(v string) *StringFactor {
+	return &StringFactor{Value: v, Decoded: token.DecodeString(v)}
+}
+func (*StringFactor) Children() Nodes { return Nodes{} }
+func (*StringFactor) isFactor()       {}
 
 // ValueFactor for true, false or other values
 
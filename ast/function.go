@@ -24,12 +24,25 @@ type ProcedureDeclSection interface {
 //   FunctionHeading ';' [Directive] [PortabilityDirective]
 //   Block ';'
 //   ```
+// - MethodDecl
+//   ```
+//   [CLASS] (PROCEDURE | FUNCTION | CONSTRUCTOR | DESTRUCTOR) TypeId '.' [Ident '.']... Ident [FormalParameters] [':' ReturnType] ';'
+//   [Directive] Block ';'
+//   ```
+//   (MethodDecl is not defined in the grammar of Object Pascal Language Guide.
+//   It implements a method declared in a class type.)
 type FunctionDecl struct {
 	*FunctionHeading
 	Directives           []Directive
 	ExternalOptions      *ExternalOptions
 	PortabilityDirective *PortabilityDirective
 	Block                *Block
+
+	ClassMethod bool         // true for CLASS PROCEDURE or CLASS FUNCTION
+	ClassTypes  []*IdentRef  // TOuter and TInner for TOuter.TInner.Method. nil for non-method.
+	Method      *ClassMethod // the declaration of the method in the class type
+
+	CompilerDirectives CompilerDirectives // the compiler directives just before the declaration
 }
 
 var _ astcore.DeclNode = (*FunctionDecl)(nil)
@@ -39,8 +52,37 @@ var _ ProcedureDeclSection = (*FunctionDecl)(nil)
 func (*FunctionDecl) canBeDeclSection()       {}
 func (*FunctionDecl) isProcedureDeclSection() {}
 func (m *FunctionDecl) Children() Nodes {
-	return Nodes{m.FunctionHeading, m.Block}
+	r := Nodes{}
+	for _, i := range m.ClassTypes {
+		r = append(r, i)
+	}
+	return append(r, m.FunctionHeading, m.Block)
+}
+
+// IsMethod returns true if the function is an implementation of a method.
+func (m *FunctionDecl) IsMethod() bool {
+	return len(m.ClassTypes) > 0
 }
 func (m *FunctionDecl) ToDeclarations() astcore.Decls {
 	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
 }
+
+// Self is the implicit identifier in method bodies.
+// It refers the instance, or the class in class methods.
+type Self struct {
+	*Ident
+	TypeDecl *TypeDecl
+}
+
+var _ astcore.DeclNode = (*Self)(nil)
+
+func NewSelf(typeDecl *TypeDecl) *Self {
+	return &Self{Ident: &Ident{Name: "Self"}, TypeDecl: typeDecl}
+}
+
+func (m *Self) Children() Nodes {
+	return Nodes{}
+}
+func (m *Self) ToDeclarations() astcore.Decls {
+	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
+}
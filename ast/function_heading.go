@@ -34,6 +34,9 @@ type FunctionType uint
 const (
 	FtProcedure FunctionType = iota
 	FtFunction
+	FtConstructor // only for method implementation
+	FtDestructor  // only for method implementation
+	FtOperator    // only for method implementation of records
 )
 
 func (m *ExportedHeading) ToDeclarations() astcore.Decls {
@@ -42,18 +45,19 @@ func (m *ExportedHeading) ToDeclarations() astcore.Decls {
 
 // - FunctionHeading
 //   ```
-//   FUNCTION Ident [FormalParameters] ':' (SimpleType | STRING)
+//   FUNCTION Ident [TypeParams] [FormalParameters] ':' (SimpleType | STRING)
 //   ```
 //   (Actually ReturnType is not only SimpleType or STRING.
 //   TypeId also can be ReturnType.)
 // - ProcedureHeading
 //   ```
-//   PROCEDURE Ident [FormalParameters]
+//   PROCEDURE Ident [TypeParams] [FormalParameters]
 //   ```
 
 type FunctionHeading struct {
 	Type FunctionType
 	*Ident
+	TypeParams       TypeParams
 	FormalParameters FormalParameters
 	ReturnType       *TypeId
 }
@@ -68,6 +72,9 @@ func (*FunctionHeading) isInterfaceMethodHeading() {}
 func (s *FunctionHeading) GetIdent() *Ident { return s.Ident }
 func (s *FunctionHeading) Children() Nodes {
 	r := Nodes{s.Ident}
+	if s.TypeParams != nil {
+		r = append(r, s.TypeParams)
+	}
 	if s.FormalParameters != nil {
 		r = append(r, s.FormalParameters)
 	}
@@ -93,7 +100,7 @@ func (s FormalParameters) Children() Nodes {
 
 // - FormalParm
 //   ```
-//   [VAR | CONST | OUT] Parameter
+//   [Attributes] [VAR | CONST | OUT] [Attributes] Parameter
 //   ```
 type FormalParmOption string
 
@@ -104,14 +111,19 @@ var (
 )
 
 type FormalParm struct {
-	Opt *FormalParmOption
+	Attributes Attributes
+	Opt        *FormalParmOption
 	*Parameter
 }
 
 var _ astcore.DeclNode = (*FormalParm)(nil)
 
 func (m *FormalParm) Children() Nodes {
-	return Nodes{m.Parameter}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	return append(r, m.Parameter)
 }
 
 func NewFormalParm(name interface{}, args ...interface{}) *FormalParm {
@@ -0,0 +1,52 @@
+package ast
+
+import "github.com/akm/tparser/ast/astcore"
+
+// - Library
+//   ```
+//   LIBRARY Ident ';'
+//   ProgramBlock '.'
+//   ```
+type Library struct {
+	Path string
+	*Ident
+	ProgramBlock       *ProgramBlock
+	DeclMap            astcore.DeclMap
+	CompilerDirectives CompilerDirectives
+}
+
+var _ Goal = (*Library)(nil)
+var _ Namespace = (*Library)(nil)
+
+func (*Library) isGoal() {}
+func (m *Library) GetPath() string {
+	return m.Path
+}
+func (m *Library) Children() Nodes {
+	return Nodes{m.Ident, m.ProgramBlock}
+}
+func (m *Library) ToDeclarations() astcore.Decls {
+	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
+}
+
+func (m *Library) GetIdent() *Ident {
+	return m.Ident
+}
+
+func (m *Library) GetDeclMap() astcore.DeclMap {
+	return m.DeclMap
+}
+
+// ExportsItems returns the items of all exports statements in the library.
+func (m *Library) ExportsItems() []*ExportsItem {
+	r := []*ExportsItem{}
+	if m.ProgramBlock == nil || m.ProgramBlock.Block == nil {
+		return r
+	}
+	for _, stmts := range []ExportsStmts{m.ProgramBlock.ExportsStmts1, m.ProgramBlock.ExportsStmts2} {
+		for _, stmt := range stmts {
+			r = append(r, stmt.ExportsItems...)
+		}
+	}
+	return r
+}
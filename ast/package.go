@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/akm/tparser/ast/astcore"
+)
+
+// - Package
+//   ```
+//   PACKAGE Ident ';'
+//   [RequiresClause]
+//   [ContainsClause]
+//   END '.'
+//   ```
+type Package struct {
+	Path string
+	*Ident
+	RequiresClause     RequiresClause // optional
+	ContainsClause     ContainsClause // optional
+	DeclMap            astcore.DeclMap
+	CompilerDirectives CompilerDirectives
+}
+
+var _ Goal = (*Package)(nil)
+var _ Namespace = (*Package)(nil)
+
+func (*Package) isGoal() {}
+func (m *Package) GetPath() string {
+	return m.Path
+}
+func (m *Package) Children() Nodes {
+	r := Nodes{m.Ident}
+	if m.RequiresClause != nil {
+		r = append(r, m.RequiresClause)
+	}
+	if m.ContainsClause != nil {
+		r = append(r, m.ContainsClause)
+	}
+	return r
+}
+func (m *Package) ToDeclarations() astcore.Decls {
+	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
+}
+
+func (m *Package) GetIdent() *Ident {
+	return m.Ident
+}
+
+func (m *Package) GetDeclMap() astcore.DeclMap {
+	return m.DeclMap
+}
+
+// - RequiresClause
+//   ```
+//   REQUIRES IdentList... ';'
+//   ```
+type RequiresClause []*RequiresClauseItem
+
+var _ Node = (RequiresClause)(nil)
+
+func (s RequiresClause) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+func (s RequiresClause) Find(name string) *RequiresClauseItem {
+	for _, i := range s {
+		if strings.EqualFold(i.Ident.Name, name) {
+			return i
+		}
+	}
+	return nil
+}
+
+// RequiresClauseItem is a package name in RequiresClause.
+// Package is set when the required package is loaded.
+type RequiresClauseItem struct {
+	*Ident
+	Package *Package
+}
+
+var _ Node = (*RequiresClauseItem)(nil)
+
+func (m *RequiresClauseItem) Children() Nodes {
+	return Nodes{m.Ident}
+}
+
+// - ContainsClause
+//   ```
+//   CONTAINS IdentList... ';'
+//   ```
+// Like the uses clause of a program, any unit name may be followed by
+// the reserved word in and the name of a source file in single quotation marks.
+type ContainsClause []*UsesClauseItem
+
+var _ Node = (ContainsClause)(nil)
+
+func (s ContainsClause) Children() Nodes {
+	return UsesClause(s).Children()
+}
+
+func (s ContainsClause) Find(name string) *UsesClauseItem {
+	return UsesClause(s).Find(name)
+}
+
+func (s ContainsClause) Units() Units {
+	return UsesClause(s).Units()
+}
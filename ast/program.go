@@ -15,8 +15,9 @@ type Program struct {
 	*Ident
 	// IdentList    *IdentList // Borland’s Object Pascal compiler ignores these parameters.
 
-	ProgramBlock *ProgramBlock
-	DeclMap      astcore.DeclMap
+	ProgramBlock       *ProgramBlock
+	DeclMap            astcore.DeclMap
+	CompilerDirectives CompilerDirectives
 }
 
 var _ Goal = (*Program)(nil)
@@ -39,8 +39,9 @@ func (s StmtList) Children() Nodes {
 //   [LabelId ':'] [SimpleStatement | StructStmt]
 //   ```
 type Statement struct {
-	LabelId *LabelId
-	Body    StatementBody
+	LabelId            *LabelId
+	Body               StatementBody
+	CompilerDirectives CompilerDirectives // the compiler directives just before the statement
 }
 
 var _ Node = (*Statement)(nil)
@@ -71,6 +72,10 @@ func (m *Statement) Children() Nodes {
 //   ```
 //   GOTO LabelId
 //   ```
+//   (InlineVarStatement)
+//   ```
+//   VAR IdentList [':' Type] [':=' Expression]
+//   ```
 // - StructStmt
 //   ```
 //   CompoundStmt
@@ -188,6 +193,38 @@ func (*GotoStatement) isStatementBody()   {}
 func (*GotoStatement) isSimpleStatement() {}
 func (m *GotoStatement) Children() Nodes  { return Nodes{m.LabelId} }
 
+//   (InlineVarStatement)
+//   ```
+//   VAR IdentList [':' Type] [':=' Expression]
+//   ```
+// Inline variables are visible until the end of the enclosing CompoundStmt.
+// The variable declared by FOR VAR is also an InlineVarStatement.
+type InlineVarStatement struct {
+	IdentList  IdentList
+	Type       Type        // nil if the type is inferred
+	Expression *Expression // nil able
+	Inferred   bool        // true if the type is inferred from the value
+}
+
+var _ SimpleStatement = (*InlineVarStatement)(nil)
+var _ astcore.DeclNode = (*InlineVarStatement)(nil)
+
+func (*InlineVarStatement) isStatementBody()   {}
+func (*InlineVarStatement) isSimpleStatement() {}
+func (m *InlineVarStatement) Children() Nodes {
+	r := Nodes{m.IdentList}
+	if m.Type != nil {
+		r = append(r, m.Type)
+	}
+	if m.Expression != nil {
+		r = append(r, m.Expression)
+	}
+	return r
+}
+func (m *InlineVarStatement) ToDeclarations() astcore.Decls {
+	return astcore.NewDeclarations(m.IdentList, m)
+}
+
 // - ConditionalStmt
 //   ```
 //   IfStmt
@@ -330,6 +367,9 @@ func (m *CaseLabel) Children() Nodes {
 //   ```
 //   ForStmt
 //   ```
+//   ```
+//   ForInStmt
+//   ```
 type LoopStmt interface {
 	StructStmt
 	isLoopStmt()
@@ -375,10 +415,15 @@ func (m *WhileStmt) Children() Nodes {
 
 // - ForStmt
 //   ```
-//   FOR QualId ':=' Expression (TO | DOWNTO) Expression DO Statement
+//   FOR (QualId | ForVar) ':=' Expression (TO | DOWNTO) Expression DO Statement
+//   ```
+// - ForVar
+//   ```
+//   VAR Ident [':' Type]
 //   ```
 type ForStmt struct {
 	QualId    *QualId
+	Var       *InlineVarStatement // declared by FOR VAR. QualId refers it. nil able
 	Initial   *Expression
 	Terminal  *Expression
 	Down      bool // false: TO, true: DOWNTO
@@ -392,7 +437,36 @@ func (*ForStmt) isStatementBody() {}
 func (*ForStmt) isStructStmt()    {}
 func (*ForStmt) isLoopStmt()      {}
 func (m *ForStmt) Children() Nodes {
-	return Nodes{m.QualId, m.Initial, m.Terminal, m.Statement}
+	r := Nodes{}
+	if m.Var != nil {
+		r = append(r, m.Var)
+	}
+	return append(r, m.QualId, m.Initial, m.Terminal, m.Statement)
+}
+
+// - ForInStmt
+//   ```
+//   FOR (QualId | ForVar) IN Expression DO Statement
+//   ```
+type ForInStmt struct {
+	QualId     *QualId
+	Var        *InlineVarStatement // declared by FOR VAR. QualId refers it. nil able
+	Enumerable *Expression
+	Statement  *Statement
+}
+
+var _ StructStmt = (*ForInStmt)(nil)
+var _ LoopStmt = (*ForInStmt)(nil)
+
+func (*ForInStmt) isStatementBody() {}
+func (*ForInStmt) isStructStmt()    {}
+func (*ForInStmt) isLoopStmt()      {}
+func (m *ForInStmt) Children() Nodes {
+	r := Nodes{}
+	if m.Var != nil {
+		r = append(r, m.Var)
+	}
+	return append(r, m.QualId, m.Enumerable, m.Statement)
 }
 
 // - WithStmt
@@ -546,20 +620,3 @@ func (m *RaiseStmt) Children() Nodes {
 	}
 	return r
 }
-
-// - AssemblerStatement
-//   ```
-//   ASM
-//   <assemblylanguage>
-//   END
-//   ```
-type AssemblerStatement struct {
-}
-
-var _ StructStmt = (*AssemblerStatement)(nil)
-var _ BlockBody = (*AssemblerStatement)(nil)
-
-func (*AssemblerStatement) isStatementBody() {}
-func (*AssemblerStatement) isStructStmt()    {}
-func (*AssemblerStatement) isBlockBody()     {}
-func (*AssemblerStatement) Children() Nodes  { return Nodes{} }
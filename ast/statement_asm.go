@@ -0,0 +1,304 @@
+package ast
+
+import "github.com/akm/tparser/ast/astcore"
+
+// - AssemblerStatement
+//   ```
+//   ASM
+//   AsmStatement ...
+//   END
+//   ```
+type AssemblerStatement struct {
+	Statements AsmStatements
+}
+
+var _ StructStmt = (*AssemblerStatement)(nil)
+var _ BlockBody = (*AssemblerStatement)(nil)
+
+func (*AssemblerStatement) isStatementBody() {}
+func (*AssemblerStatement) isStructStmt()    {}
+func (*AssemblerStatement) isBlockBody()     {}
+func (m *AssemblerStatement) Children() Nodes {
+	r := Nodes{}
+	if m.Statements != nil {
+		r = append(r, m.Statements)
+	}
+	return r
+}
+
+type AsmStatements []AsmStatement
+
+var _ Node = (AsmStatements)(nil)
+
+func (s AsmStatements) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - AsmStatement
+//   ```
+//   AsmLabel
+//   ```
+//   ```
+//   AsmInstruction
+//   ```
+//   ```
+//   AsmDirective
+//   ```
+// Each AsmInstruction and AsmDirective ends at the end of line or ';'.
+type AsmStatement interface {
+	Node
+	isAsmStatement()
+}
+
+// - AsmLabel
+//   ```
+//   Ident ':'
+//   ```
+// Labels starting with '@' such as @@loop are local to the asm block.
+type AsmLabel struct {
+	*Ident
+}
+
+var _ AsmStatement = (*AsmLabel)(nil)
+var _ astcore.DeclNode = (*AsmLabel)(nil)
+
+func (*AsmLabel) isAsmStatement() {}
+func (m *AsmLabel) Children() Nodes {
+	return Nodes{m.Ident}
+}
+func (m *AsmLabel) ToDeclarations() astcore.Decls {
+	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
+}
+
+// - AsmInstruction
+//   ```
+//   [Prefix] Opcode [AsmOperand ','...]
+//   ```
+// Prefix is one of LOCK, REP, REPE, REPZ, REPNE and REPNZ.
+type AsmInstruction struct {
+	Prefix   *Ident
+	Opcode   *Ident
+	Operands AsmOperands
+}
+
+var _ AsmStatement = (*AsmInstruction)(nil)
+
+func (*AsmInstruction) isAsmStatement() {}
+func (m *AsmInstruction) Children() Nodes {
+	r := Nodes{}
+	if m.Prefix != nil {
+		r = append(r, m.Prefix)
+	}
+	r = append(r, m.Opcode)
+	if m.Operands != nil {
+		r = append(r, m.Operands)
+	}
+	return r
+}
+
+// - AsmDirective
+//   ```
+//   (DB | DW | DD | DQ) AsmOperand ','...
+//   ```
+//   ```
+//   ALIGN AsmOperand
+//   ```
+//   ```
+//   ('.NOFRAME' | '.PARAMS' | '.PUSHNV' | '.SAVENV') [AsmOperand]
+//   ```
+type AsmDirective struct {
+	*Ident
+	Operands AsmOperands
+}
+
+var _ AsmStatement = (*AsmDirective)(nil)
+
+func (*AsmDirective) isAsmStatement() {}
+func (m *AsmDirective) Children() Nodes {
+	r := Nodes{m.Ident}
+	if m.Operands != nil {
+		r = append(r, m.Operands)
+	}
+	return r
+}
+
+type AsmOperands []AsmOperand
+
+var _ Node = (AsmOperands)(nil)
+
+func (s AsmOperands) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - AsmOperand
+//   ```
+//   AsmRegister
+//   ```
+//   ```
+//   AsmNumber
+//   ```
+//   ```
+//   AsmString
+//   ```
+//   ```
+//   AsmIdent
+//   ```
+//   ```
+//   AsmMemoryRef
+//   ```
+//   ```
+//   AsmPtr
+//   ```
+//   ```
+//   AsmUnaryExpr
+//   ```
+//   ```
+//   AsmBinaryExpr
+//   ```
+type AsmOperand interface {
+	Node
+	isAsmOperand()
+}
+
+// - AsmRegister
+//   ```
+//   <register> ['(' AsmNumber ')']
+//   ```
+// Index is used for FPU registers such as ST(1).
+type AsmRegister struct {
+	*Ident
+	Index *AsmNumber
+}
+
+var _ AsmOperand = (*AsmRegister)(nil)
+
+func (*AsmRegister) isAsmOperand() {}
+func (m *AsmRegister) Children() Nodes {
+	r := Nodes{m.Ident}
+	if m.Index != nil {
+		r = append(r, m.Index)
+	}
+	return r
+}
+
+// - AsmNumber
+//   ```
+//   <number> such as 10, 0FFH, $FF or 1.5
+//   ```
+type AsmNumber struct {
+	Value string
+}
+
+var _ AsmOperand = (*AsmNumber)(nil)
+
+func (*AsmNumber) isAsmOperand()   {}
+func (*AsmNumber) Children() Nodes { return Nodes{} }
+
+// - AsmString
+//   ```
+//   <string> quoted by "'" or '"'
+//   ```
+type AsmString struct {
+	Value string
+}
+
+var _ AsmOperand = (*AsmString)(nil)
+
+func (*AsmString) isAsmOperand()   {}
+func (*AsmString) Children() Nodes { return Nodes{} }
+
+// - AsmIdent
+//   ```
+//   Ident
+//   ```
+// AsmIdent refers a label, or an identifier declared in Object Pascal such as
+// a variable, a parameter, a constant, a type or a member of them.
+type AsmIdent struct {
+	*IdentRef
+}
+
+var _ AsmOperand = (*AsmIdent)(nil)
+
+func (*AsmIdent) isAsmOperand() {}
+func (m *AsmIdent) Children() Nodes {
+	return Nodes{m.IdentRef}
+}
+
+// - AsmMemoryRef
+//   ```
+//   [AsmRegister ':'] '[' AsmOperand ']'
+//   ```
+type AsmMemoryRef struct {
+	Segment *AsmRegister
+	Operand AsmOperand
+}
+
+var _ AsmOperand = (*AsmMemoryRef)(nil)
+
+func (*AsmMemoryRef) isAsmOperand() {}
+func (m *AsmMemoryRef) Children() Nodes {
+	r := Nodes{}
+	if m.Segment != nil {
+		r = append(r, m.Segment)
+	}
+	r = append(r, m.Operand)
+	return r
+}
+
+// - AsmPtr
+//   ```
+//   (BYTE | WORD | DWORD | QWORD | TBYTE | DQWORD) PTR AsmOperand
+//   ```
+type AsmPtr struct {
+	Size    string
+	Operand AsmOperand
+}
+
+var _ AsmOperand = (*AsmPtr)(nil)
+
+func (*AsmPtr) isAsmOperand() {}
+func (m *AsmPtr) Children() Nodes {
+	return Nodes{m.Operand}
+}
+
+// - AsmUnaryExpr
+//   ```
+//   ('+' | '-' | NOT | OFFSET | VMTOFFSET | DMTINDEX | TYPE | HIGH | LOW | SHORT) AsmOperand
+//   ```
+type AsmUnaryExpr struct {
+	Op      string
+	Operand AsmOperand
+}
+
+var _ AsmOperand = (*AsmUnaryExpr)(nil)
+
+func (*AsmUnaryExpr) isAsmOperand() {}
+func (m *AsmUnaryExpr) Children() Nodes {
+	return Nodes{m.Operand}
+}
+
+// - AsmBinaryExpr
+//   ```
+//   AsmOperand ('+' | '-' | '*' | '/' | OR | XOR | AND | MOD | SHL | SHR | '.') AsmOperand
+//   ```
+// `X[EBX]` is AsmBinaryExpr whose Op is '+' and Right is AsmMemoryRef.
+type AsmBinaryExpr struct {
+	Left  AsmOperand
+	Op    string
+	Right AsmOperand
+}
+
+var _ AsmOperand = (*AsmBinaryExpr)(nil)
+
+func (*AsmBinaryExpr) isAsmOperand() {}
+func (m *AsmBinaryExpr) Children() Nodes {
+	return Nodes{m.Left, m.Right}
+}
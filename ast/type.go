@@ -12,9 +12,11 @@ type TypeSection []*TypeDecl
 
 var _ Node = (TypeSection)(nil)
 var _ InterfaceDecl = (TypeSection)(nil)
+var _ NestedDeclSection = (TypeSection)(nil)
 
-func (TypeSection) canBeInterfaceDecl() {}
-func (TypeSection) canBeDeclSection()   {}
+func (TypeSection) canBeInterfaceDecl()     {}
+func (TypeSection) canBeDeclSection()       {}
+func (TypeSection) canBeNestedDeclSection() {}
 func (s TypeSection) Children() Nodes {
 	r := make(Nodes, len(s))
 	for i, m := range s {
@@ -32,21 +34,33 @@ func (s TypeSection) GetDeclNodes() astcore.DeclNodes {
 
 // - TypeDecl
 //   ```
-//   Ident '=' [TYPE] Type [PortabilityDirective]
+//   Ident [TypeParams] '=' [TYPE] Type [PortabilityDirective]
 //   ```
 //   ```
-//   Ident '=' [TYPE] RestrictedType [PortabilityDirective]
+//   [Attributes] Ident [TypeParams] '=' [TYPE] RestrictedType [PortabilityDirective]
 //   ```
 type TypeDecl struct {
+	Attributes Attributes
 	*Ident
+	TypeParams           TypeParams
 	Type                 Type
 	PortabilityDirective *PortabilityDirective
+	CompilerDirectives   CompilerDirectives // the compiler directives just before the declaration
 }
 
 var _ astcore.DeclNode = (*TypeDecl)(nil)
 
 func (m *TypeDecl) Children() Nodes {
-	return Nodes{m.Ident, m.Type}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	r = append(r, m.Ident)
+	if m.TypeParams != nil {
+		r = append(r, m.TypeParams)
+	}
+	r = append(r, m.Type)
+	return r
 }
 
 func (m *TypeDecl) ToDeclarations() astcore.Decls {
@@ -18,6 +18,17 @@ type ObjectType interface {
 	IsObjectType() bool
 }
 
+// ClassMembersType is implemented by the types which consist of ClassMemberSections,
+// CustomClassType, CustomObjectType and RecType.
+type ClassMembersType interface {
+	Type
+	AddMemberSection(section *ClassMemberSection)
+	FindMemberDecl(name string, access *MemberAccess) *astcore.Decl
+	FindProperty(name string, acendant bool) *ClassProperty
+	FindMethods(name string) []*ClassMethod
+	MemberDecls(access *MemberAccess) astcore.Decls
+}
+
 // - ForwardDeclaredClassType
 //   ```
 //   CLASS
@@ -46,16 +57,19 @@ func (m *ForwardDeclaredClassType) Children() Nodes {
 
 // - ClassType
 //   ```
-//   CLASS [ClassHeritage]
+//   CLASS [ABSTRACT | SEALED] [ClassHeritage]
 //   [ClassMemberSection]
 //   END
 //   ```
 type CustomClassType struct {
+	Abstract bool
+	Sealed   bool
 	Heritage ClassHeritage
 	Members  ClassMemberSections
 }
 
 var _ ClassType = (*CustomClassType)(nil)
+var _ ClassMembersType = (*CustomClassType)(nil)
 
 func (*CustomClassType) isType()           {}
 func (*CustomClassType) IsClassType() bool { return true }
@@ -70,6 +84,10 @@ func (m *CustomClassType) Children() Nodes {
 	return r
 }
 
+func (m *CustomClassType) AddMemberSection(section *ClassMemberSection) {
+	m.Members = append(m.Members, section)
+}
+
 func (m *CustomClassType) GetParentClass() *CustomClassType {
 	if m.Heritage != nil && len(m.Heritage) > 0 {
 		parent := m.Heritage[0]
@@ -84,53 +102,22 @@ func (m *CustomClassType) GetParentClass() *CustomClassType {
 	return nil
 }
 
-func (m *CustomClassType) FindMemberDecl(name string, includePrivate bool) *astcore.Decl {
+// FindMemberDecl returns the member of the class or its ancestors which can be accessed from access.
+func (m *CustomClassType) FindMemberDecl(name string, access *MemberAccess) *astcore.Decl {
 	defer log.TraceMethod("CustomClassType.FindMemberDecl: " + name)()
 
-	kw := strings.ToLower(name)
-	for _, mb := range m.Members {
-		if !includePrivate && (mb.Visibility == CvPrivate) {
-			continue
-		}
-		if mb.ClassFieldList != nil {
-			for _, f := range mb.ClassFieldList {
-				if r := f.IdentList.Find(kw); r != nil {
-					return f.ToDeclarations().Find(kw)
-				}
-			}
-		}
-		if mb.ClassMethodList != nil {
-			for _, method := range mb.ClassMethodList {
-				if strings.ToLower(method.Heading.GetIdent().Name) == kw {
-					return method.ToDeclarations().Find(kw)
-				}
-			}
-		}
-		if mb.ClassPropertyList != nil {
-			for _, prop := range mb.ClassPropertyList {
-				if strings.ToLower(prop.Ident.Name) == kw {
-					return prop.ToDeclarations().Find(kw)
-				}
-			}
-		}
+	if r := m.Members.findMemberDecl(name, m, access); r != nil {
+		return r
 	}
-
 	if parentClass := m.GetParentClass(); parentClass != nil {
-		return parentClass.FindMemberDecl(name, false)
+		return parentClass.FindMemberDecl(name, access)
 	}
 	return nil
 }
 
 func (m *CustomClassType) FindProperty(name string, acendant bool) *ClassProperty {
-	kw := strings.ToLower(name)
-	for _, mm := range m.Members {
-		if mm.ClassPropertyList != nil {
-			for _, prop := range mm.ClassPropertyList {
-				if strings.ToLower(prop.Ident.Name) == kw {
-					return prop
-				}
-			}
-		}
+	if r := m.Members.findProperty(name); r != nil {
+		return r
 	}
 	if acendant {
 		if parentClass := m.GetParentClass(); parentClass != nil {
@@ -140,6 +127,22 @@ func (m *CustomClassType) FindProperty(name string, acendant bool) *ClassPropert
 	return nil
 }
 
+// FindMethods returns the methods named name which are declared in the class, not in its ancestors.
+func (m *CustomClassType) FindMethods(name string) []*ClassMethod {
+	return m.Members.findMethods(name)
+}
+
+// MemberDecls returns the declarations of the members including inherited ones.
+// Inherited members come before the members of the class, so the latter
+// take precedence when they are registered in order.
+func (m *CustomClassType) MemberDecls(access *MemberAccess) astcore.Decls {
+	r := astcore.Decls{}
+	if parentClass := m.GetParentClass(); parentClass != nil {
+		r = append(r, parentClass.MemberDecls(access)...)
+	}
+	return append(r, m.Members.memberDecls(m, access)...)
+}
+
 // - ObjectType
 //   ```
 //   OBJECT [ClassHeritage]
@@ -152,6 +155,7 @@ type CustomObjectType struct {
 }
 
 var _ ObjectType = (*CustomObjectType)(nil)
+var _ ClassMembersType = (*CustomObjectType)(nil)
 
 func (*CustomObjectType) isType()            {}
 func (*CustomObjectType) IsObjectType() bool { return true }
@@ -166,6 +170,64 @@ func (m *CustomObjectType) Children() Nodes {
 	return r
 }
 
+func (m *CustomObjectType) AddMemberSection(section *ClassMemberSection) {
+	m.Members = append(m.Members, section)
+}
+
+func (m *CustomObjectType) GetParentObject() *CustomObjectType {
+	if m.Heritage != nil && len(m.Heritage) > 0 {
+		parent := m.Heritage[0]
+		if parent.Ref != nil {
+			if typeDecl, ok := parent.Ref.Node.(*TypeDecl); ok {
+				if parentObject, ok := typeDecl.Type.(*CustomObjectType); ok {
+					return parentObject
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// FindMemberDecl returns the member of the object or its ancestors which can be accessed from access.
+func (m *CustomObjectType) FindMemberDecl(name string, access *MemberAccess) *astcore.Decl {
+	defer log.TraceMethod("CustomObjectType.FindMemberDecl: " + name)()
+
+	if r := m.Members.findMemberDecl(name, m, access); r != nil {
+		return r
+	}
+	if parentObject := m.GetParentObject(); parentObject != nil {
+		return parentObject.FindMemberDecl(name, access)
+	}
+	return nil
+}
+
+func (m *CustomObjectType) FindProperty(name string, acendant bool) *ClassProperty {
+	if r := m.Members.findProperty(name); r != nil {
+		return r
+	}
+	if acendant {
+		if parentObject := m.GetParentObject(); parentObject != nil {
+			return parentObject.FindProperty(name, true)
+		}
+	}
+	return nil
+}
+
+// FindMethods returns the methods named name which are declared in the object, not in its ancestors.
+func (m *CustomObjectType) FindMethods(name string) []*ClassMethod {
+	return m.Members.findMethods(name)
+}
+
+// MemberDecls returns the declarations of the members including inherited ones
+// in the same order as CustomClassType.MemberDecls.
+func (m *CustomObjectType) MemberDecls(access *MemberAccess) astcore.Decls {
+	r := astcore.Decls{}
+	if parentObject := m.GetParentObject(); parentObject != nil {
+		r = append(r, parentObject.MemberDecls(access)...)
+	}
+	return append(r, m.Members.memberDecls(m, access)...)
+}
+
 // - ClassHeritage
 //   ```
 //   '(' TypeId ',' ... ')'
@@ -198,24 +260,118 @@ func (s ClassMemberSections) Children() Nodes {
 	return r
 }
 
+// findMemberDecl returns the member declared in owner which can be accessed from access.
+func (s ClassMemberSections) findMemberDecl(name string, owner ClassMembersType, access *MemberAccess) *astcore.Decl {
+	kw := strings.ToLower(name)
+	for _, mb := range s {
+		if !access.CanAccess(owner, mb.Visibility) {
+			continue
+		}
+		if mb.ClassFieldList != nil {
+			for _, f := range mb.ClassFieldList {
+				if r := f.IdentList.Find(kw); r != nil {
+					return f.ToDeclarations().Find(kw)
+				}
+			}
+		}
+		if mb.ClassMethodList != nil {
+			for _, method := range mb.ClassMethodList {
+				if strings.ToLower(method.Heading.GetIdent().Name) == kw {
+					return method.ToDeclarations().Find(kw)
+				}
+			}
+		}
+		if mb.ClassPropertyList != nil {
+			for _, prop := range mb.ClassPropertyList {
+				if strings.ToLower(prop.Ident.Name) == kw {
+					return prop.ToDeclarations().Find(kw)
+				}
+			}
+		}
+		for _, sect := range mb.NestedDeclSections {
+			for _, node := range sect.GetDeclNodes() {
+				if r := node.ToDeclarations().Find(kw); r != nil {
+					return r
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s ClassMemberSections) findProperty(name string) *ClassProperty {
+	kw := strings.ToLower(name)
+	for _, mm := range s {
+		if mm.ClassPropertyList != nil {
+			for _, prop := range mm.ClassPropertyList {
+				if strings.ToLower(prop.Ident.Name) == kw {
+					return prop
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s ClassMemberSections) findMethods(name string) []*ClassMethod {
+	r := []*ClassMethod{}
+	for _, mb := range s {
+		for _, method := range mb.ClassMethodList {
+			if strings.EqualFold(method.Heading.GetIdent().Name, name) {
+				r = append(r, method)
+			}
+		}
+	}
+	return r
+}
+
+func (s ClassMemberSections) memberDecls(owner ClassMembersType, access *MemberAccess) astcore.Decls {
+	r := astcore.Decls{}
+	for _, mb := range s {
+		if !access.CanAccess(owner, mb.Visibility) {
+			continue
+		}
+		for _, f := range mb.ClassFieldList {
+			r = append(r, f.ToDeclarations()...)
+		}
+		for _, method := range mb.ClassMethodList {
+			r = append(r, method.ToDeclarations()...)
+		}
+		for _, prop := range mb.ClassPropertyList {
+			r = append(r, prop.ToDeclarations()...)
+		}
+		for _, sect := range mb.NestedDeclSections {
+			for _, node := range sect.GetDeclNodes() {
+				r = append(r, node.ToDeclarations()...)
+			}
+		}
+	}
+	return r
+}
+
 // - ClassMemberSection
 //   ```
 //   ClassVisibility
-//   [ClassFieldList]
+//   [NestedDeclSection ...]
+//   [[[CLASS] VAR] ClassFieldList]
 //   [ClassMethodList]
 //   [ClassPropertyList]
 //   ```
 type ClassMemberSection struct {
-	Visibility        ClassVisibility
-	ClassFieldList    ClassFieldList
-	ClassMethodList   ClassMethodList
-	ClassPropertyList ClassPropertyList
+	Visibility         ClassVisibility
+	NestedDeclSections NestedDeclSections
+	ClassFieldList     ClassFieldList
+	ClassMethodList    ClassMethodList
+	ClassPropertyList  ClassPropertyList
 }
 
 var _ Node = (*ClassMemberSection)(nil)
 
 func (m *ClassMemberSection) Children() Nodes {
 	r := Nodes{}
+	if m.NestedDeclSections != nil {
+		r = append(r, m.NestedDeclSections)
+	}
 	if m.ClassFieldList != nil {
 		r = append(r, m.ClassFieldList)
 	}
@@ -228,20 +384,112 @@ func (m *ClassMemberSection) Children() Nodes {
 	return r
 }
 
+// - NestedDeclSection
+//   ```
+//   ConstSection
+//   ```
+//   ```
+//   TypeSection
+//   ```
+type NestedDeclSection interface {
+	Node
+	canBeNestedDeclSection()
+	GetDeclNodes() astcore.DeclNodes
+}
+
+type NestedDeclSections []NestedDeclSection
+
+var _ Node = (NestedDeclSections)(nil)
+
+func (s NestedDeclSections) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
 // - ClassVisibility
 //   ```
-//   [PUBLIC | PROTECTED | PRIVATE | PUBLISHED]
+//   [PUBLIC | PROTECTED | PRIVATE | PUBLISHED | STRICT PRIVATE | STRICT PROTECTED]
 //   ```
 type ClassVisibility string
 
 const (
-	CvDefault   ClassVisibility = "default" // implicitly public
-	CvPrivate   ClassVisibility = "PRIVATE"
-	CvProtected ClassVisibility = "PROTECTED"
-	CvPublic    ClassVisibility = "PUBLIC"
-	CvPublished ClassVisibility = "PUBLISHED"
+	CvDefault         ClassVisibility = "default" // implicitly public
+	CvPrivate         ClassVisibility = "PRIVATE"
+	CvProtected       ClassVisibility = "PROTECTED"
+	CvPublic          ClassVisibility = "PUBLIC"
+	CvPublished       ClassVisibility = "PUBLISHED"
+	CvStrictPrivate   ClassVisibility = "STRICT PRIVATE"
+	CvStrictProtected ClassVisibility = "STRICT PROTECTED"
 )
 
+// MemberAccess is the place where the members of class, object, record and
+// helper types are accessed from. A nil MemberAccess can access all the members.
+type MemberAccess struct {
+	// Type is the type whose declaration or methods access the members.
+	// It is nil outside of them.
+	Type ClassMembersType
+	// InUnit returns true if typ is declared in the unit which accesses the members.
+	InUnit func(typ ClassMembersType) bool
+}
+
+// CanAccess returns true if the members in the sections of v declared in typ can be accessed.
+//
+// PRIVATE and PROTECTED members can be accessed anywhere in the unit which
+// declares typ, but STRICT PRIVATE members can be accessed only in typ itself.
+// PROTECTED and STRICT PROTECTED members can be accessed in the descendants of typ.
+func (a *MemberAccess) CanAccess(typ ClassMembersType, v ClassVisibility) bool {
+	if a == nil {
+		return true
+	}
+	switch v {
+	case CvStrictPrivate:
+		return a.Type == typ
+	case CvStrictProtected:
+		return a.isDescendantOf(typ)
+	case CvPrivate:
+		return a.Type == typ || a.inUnit(typ)
+	case CvProtected:
+		return a.isDescendantOf(typ) || a.inUnit(typ)
+	default:
+		return true
+	}
+}
+
+func (a *MemberAccess) inUnit(typ ClassMembersType) bool {
+	return a.InUnit != nil && a.InUnit(typ)
+}
+
+// isDescendantOf returns true if Type is typ or one of its descendants.
+func (a *MemberAccess) isDescendantOf(typ ClassMembersType) bool {
+	for t := a.Type; t != nil; t = parentMembersType(t) {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func parentMembersType(typ ClassMembersType) ClassMembersType {
+	switch v := typ.(type) {
+	case *CustomClassType:
+		if r := v.GetParentClass(); r != nil {
+			return r
+		}
+	case *CustomObjectType:
+		if r := v.GetParentObject(); r != nil {
+			return r
+		}
+	case *HelperType:
+		if r := v.GetParentHelper(); r != nil {
+			return r
+		}
+	}
+	return nil
+}
+
 // - ClassFieldList
 //   ```
 //   (ClassField) ';'...
@@ -260,17 +508,23 @@ func (s ClassFieldList) Children() Nodes {
 
 // - ClassField
 //   ```
-//   IdentList ':' Type
+//   [Attributes] IdentList ':' Type
 //   ```
 type ClassField struct {
-	IdentList IdentList
-	Type      Type
+	Attributes Attributes
+	IdentList  IdentList
+	Type       Type
+	ClassVar   bool // declared after CLASS VAR
 }
 
 var _ astcore.DeclNode = (*ClassField)(nil)
 
 func (m *ClassField) Children() Nodes {
-	return Nodes{m.IdentList, m.Type}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	return append(r, m.IdentList, m.Type)
 }
 func (m *ClassField) ToDeclarations() astcore.Decls {
 	r := make(astcore.Decls, len(m.IdentList))
@@ -298,12 +552,14 @@ func (s ClassMethodList) Children() Nodes {
 
 // - ClassMethod
 //   ```
-//   [CLASS] ClassMethodHeading [';' ClassMethodDirective ...]
+//   [Attributes] [CLASS] ClassMethodHeading [';' ClassMethodDirective ...]
 //   ```
 type ClassMethod struct {
+	Attributes  Attributes
 	ClassMethod bool
 	Heading     ClassMethodHeading
 	Directives  ClassMethodDirectiveList
+	Message     *ConstExpr // the message ID of MESSAGE directive
 }
 
 var _ astcore.DeclNode = (*ClassMethod)(nil)
@@ -313,7 +569,15 @@ func (m *ClassMethod) ToDeclarations() astcore.Decls {
 }
 
 func (m *ClassMethod) Children() Nodes {
-	return Nodes{m.Heading}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	r = append(r, m.Heading)
+	if m.Message != nil {
+		r = append(r, m.Message)
+	}
+	return r
 }
 
 // - ClassMethodHeading
@@ -329,6 +593,9 @@ func (m *ClassMethod) Children() Nodes {
 //   ```
 //   DestructorHeading
 //   ```
+//   ```
+//   OperatorHeading
+//   ```
 type ClassMethodHeading interface {
 	Node
 	GetIdent() *Ident
@@ -351,6 +618,21 @@ type ClassMethodHeading interface {
 //   ```
 //   REINTRODUCE
 //   ```
+//   ```
+//   FINAL
+//   ```
+//   ```
+//   DYNAMIC
+//   ```
+//   ```
+//   MESSAGE ConstExpr
+//   ```
+//   ```
+//   STATIC
+//   ```
+//   ```
+//   CDECL | PASCAL | REGISTER | SAFECALL | STDCALL
+//   ```
 type ClassMethodDirective string
 
 const (
@@ -359,6 +641,15 @@ const (
 	CmdOverride    ClassMethodDirective = "OVERRIDE"
 	CmdOverload    ClassMethodDirective = "OVERLOAD"
 	CmdReintroduce ClassMethodDirective = "REINTRODUCE"
+	CmdFinal       ClassMethodDirective = "FINAL"
+	CmdDynamic     ClassMethodDirective = "DYNAMIC"
+	CmdMessage     ClassMethodDirective = "MESSAGE" // MESSAGE ConstExpr
+	CmdStatic      ClassMethodDirective = "STATIC"
+	CmdCdecl       ClassMethodDirective = "CDECL"
+	CmdPascal      ClassMethodDirective = "PASCAL"
+	CmdRegister    ClassMethodDirective = "REGISTER"
+	CmdSafecall    ClassMethodDirective = "SAFECALL"
+	CmdStdcall     ClassMethodDirective = "STDCALL"
 )
 
 type ClassMethodDirectiveList []ClassMethodDirective
@@ -379,6 +670,15 @@ var ClassMethodDirectives = ClassMethodDirectiveList{
 	CmdOverride,
 	CmdOverload,
 	CmdReintroduce,
+	CmdFinal,
+	CmdDynamic,
+	CmdMessage,
+	CmdStatic,
+	CmdCdecl,
+	CmdPascal,
+	CmdRegister,
+	CmdSafecall,
+	CmdStdcall,
 }
 
 // - ConstructorHeading
@@ -403,6 +703,32 @@ func (m *ConstructorHeading) Children() Nodes {
 	return r
 }
 
+// - OperatorHeading
+//   ```
+//   OPERATOR Ident [FormalParameters] ':' TypeId
+//   ```
+// Operators are declared with CLASS in records such as `class operator Add(const A, B: TVec): TVec;`.
+type OperatorHeading struct {
+	*Ident
+	FormalParameters FormalParameters
+	ReturnType       *TypeId
+}
+
+var _ ClassMethodHeading = (*OperatorHeading)(nil)
+
+func (m *OperatorHeading) isClassMethodHeading() {}
+func (m *OperatorHeading) GetIdent() *Ident      { return m.Ident }
+func (m *OperatorHeading) Children() Nodes {
+	r := Nodes{m.Ident}
+	if m.FormalParameters != nil {
+		r = append(r, m.FormalParameters)
+	}
+	if m.ReturnType != nil {
+		r = append(r, m.ReturnType)
+	}
+	return r
+}
+
 // - DestructorHeading
 //   ```
 //   DESTRUCTOR Ident
@@ -437,7 +763,7 @@ func (s ClassPropertyList) Children() Nodes {
 
 // - ClassProperty
 //   ```
-// 	 PROPERTY Ident
+// 	 [Attributes] PROPERTY Ident
 //   [PropertyInterface]
 //   [INDEX ConstExpr]
 //   [READ Ident]
@@ -446,8 +772,10 @@ func (s ClassPropertyList) Children() Nodes {
 //   [(DEFAULT ConstExpr) | NODEFAULT]
 //   [IMPLEMENTS TypeId]
 //   [PortabilityDirective]
+//   [';' DEFAULT]
 //   ```
 type ClassProperty struct {
+	Attributes           Attributes
 	Ident                *Ident
 	Interface            *PropertyInterface
 	Index                *ConstExpr
@@ -457,6 +785,7 @@ type ClassProperty struct {
 	Default              *PropertyDefaultSpecifier
 	Implements           *TypeId
 	PortabilityDirective PortabilityDirective
+	DefaultProperty      bool // the default array property with '; default'
 	// See "Property overrides and redeclarations" in Object Pascal Language Guide
 	Parent *ClassProperty
 }
@@ -467,7 +796,11 @@ func (m *ClassProperty) ToDeclarations() astcore.Decls {
 	return astcore.Decls{{Ident: m.Ident, Node: m}}
 }
 func (m *ClassProperty) Children() Nodes {
-	r := Nodes{m.Ident}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	r = append(r, m.Ident)
 	if m.Interface != nil {
 		r = append(r, m.Interface)
 	}
@@ -519,6 +852,9 @@ func (m *ClassProperty) ComputedProperty() *ClassProperty {
 	if m.Implements != nil {
 		res.Implements = m.Implements
 	}
+	if m.DefaultProperty {
+		res.DefaultProperty = true
+	}
 	return res
 }
 
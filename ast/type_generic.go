@@ -0,0 +1,110 @@
+package ast
+
+import (
+	"github.com/akm/tparser/ast/astcore"
+)
+
+// - TypeParams
+//   ```
+//   '<' TypeParamDecl ';'... '>'
+//   ```
+type TypeParams []*TypeParam
+
+var _ Node = (TypeParams)(nil)
+
+func (s TypeParams) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - TypeParamDecl
+//   ```
+//   Ident ','... [':' ConstraintList]
+//   ```
+// Each identifier of TypeParamDecl is stored as a TypeParam sharing the constraints.
+type TypeParam struct {
+	*Ident
+	Constraints TypeConstraints
+}
+
+var _ astcore.DeclNode = (*TypeParam)(nil)
+
+func (m *TypeParam) Children() Nodes {
+	r := Nodes{m.Ident}
+	if m.Constraints != nil {
+		r = append(r, m.Constraints)
+	}
+	return r
+}
+
+func (m *TypeParam) ToDeclarations() astcore.Decls {
+	return astcore.Decls{astcore.NewDeclaration(m.Ident, m)}
+}
+
+// - ConstraintList
+//   ```
+//   Constraint ','...
+//   ```
+type TypeConstraints []TypeConstraint
+
+var _ Node = (TypeConstraints)(nil)
+
+func (s TypeConstraints) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
+
+// - Constraint
+//   ```
+//   CLASS
+//   ```
+//   ```
+//   RECORD
+//   ```
+//   ```
+//   CONSTRUCTOR
+//   ```
+//   ```
+//   TypeId
+//   ```
+type TypeConstraint interface {
+	Node
+	isTypeConstraint()
+}
+
+type ConstraintKeyword string
+
+const (
+	CkClass       ConstraintKeyword = "CLASS"
+	CkRecord      ConstraintKeyword = "RECORD"
+	CkConstructor ConstraintKeyword = "CONSTRUCTOR"
+)
+
+var _ TypeConstraint = CkClass
+
+func (ConstraintKeyword) isTypeConstraint() {}
+func (ConstraintKeyword) Children() Nodes   { return Nodes{} }
+
+func (*TypeId) isTypeConstraint() {}
+
+// - TypeArgs
+//   ```
+//   '<' Type ','... '>'
+//   ```
+type TypeArgs []Type
+
+var _ Node = (TypeArgs)(nil)
+
+func (s TypeArgs) Children() Nodes {
+	r := make(Nodes, len(s))
+	for i, m := range s {
+		r[i] = m
+	}
+	return r
+}
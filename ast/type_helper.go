@@ -0,0 +1,130 @@
+package ast
+
+import (
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/log"
+)
+
+// - HelperType
+//   ```
+//   CLASS HELPER [ClassHeritage] FOR TypeId
+//   [ClassMemberSection ...]
+//   END
+//   ```
+//   ```
+//   RECORD HELPER FOR TypeId
+//   [ClassMemberSection ...]
+//   END
+//   ```
+type HelperType struct {
+	Kind     HelperKind
+	Heritage ClassHeritage
+	For      *TypeId
+	Members  ClassMemberSections
+}
+
+type HelperKind string
+
+const (
+	HkClass  HelperKind = "CLASS"
+	HkRecord HelperKind = "RECORD"
+)
+
+var _ ClassMembersType = (*HelperType)(nil)
+
+func (*HelperType) isType() {}
+func (m *HelperType) Children() Nodes {
+	r := Nodes{}
+	if m.Heritage != nil {
+		r = append(r, m.Heritage)
+	}
+	r = append(r, m.For)
+	if m.Members != nil {
+		r = append(r, m.Members)
+	}
+	return r
+}
+
+func (m *HelperType) AddMemberSection(section *ClassMemberSection) {
+	m.Members = append(m.Members, section)
+}
+
+// GetParentHelper returns the helper which the helper inherits.
+func (m *HelperType) GetParentHelper() *HelperType {
+	if m.Heritage != nil && len(m.Heritage) > 0 {
+		parent := m.Heritage[0]
+		if parent.Ref != nil {
+			if typeDecl, ok := parent.Ref.Node.(*TypeDecl); ok {
+				if parentHelper, ok := typeDecl.Type.(*HelperType); ok {
+					return parentHelper
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetHelpedType returns the type which the helper extends
+// if it consists of members.
+func (m *HelperType) GetHelpedType() ClassMembersType {
+	if m.For == nil || m.For.Ref == nil {
+		return nil
+	}
+	if typeDecl, ok := m.For.Ref.Node.(*TypeDecl); ok {
+		if r, ok := typeDecl.Type.(ClassMembersType); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// FindMemberDecl returns the member declared in the helper or its ancestor helpers.
+// The members of the helped type are not included.
+func (m *HelperType) FindMemberDecl(name string, access *MemberAccess) *astcore.Decl {
+	defer log.TraceMethod("HelperType.FindMemberDecl: " + name)()
+
+	if r := m.Members.findMemberDecl(name, m, access); r != nil {
+		return r
+	}
+	if parentHelper := m.GetParentHelper(); parentHelper != nil {
+		return parentHelper.FindMemberDecl(name, access)
+	}
+	return nil
+}
+
+func (m *HelperType) FindProperty(name string, acendant bool) *ClassProperty {
+	if r := m.Members.findProperty(name); r != nil {
+		return r
+	}
+	if acendant {
+		if parentHelper := m.GetParentHelper(); parentHelper != nil {
+			return parentHelper.FindProperty(name, true)
+		}
+	}
+	return nil
+}
+
+// FindMethods returns the methods named name which are declared in the helper, not in its ancestors.
+func (m *HelperType) FindMethods(name string) []*ClassMethod {
+	return m.Members.findMethods(name)
+}
+
+// MemberDecls returns the declarations of the members of the helped type,
+// the ancestor helpers and the helper in this order, because the methods of
+// the helper can refer all of them.
+func (m *HelperType) MemberDecls(access *MemberAccess) astcore.Decls {
+	r := astcore.Decls{}
+	if helpedType := m.GetHelpedType(); helpedType != nil {
+		r = append(r, helpedType.MemberDecls(access)...)
+	}
+	if parentHelper := m.GetParentHelper(); parentHelper != nil {
+		r = append(r, parentHelper.MemberDecls(access)...)
+	}
+	return append(r, m.Members.memberDecls(m, access)...)
+}
+
+// HelperDeclKey returns the key of the helper for the type named typeName in DeclMap.
+// The key can't be an identifier, so it never conflicts with the other declarations.
+func HelperDeclKey(typeName string) string {
+	return "helper for " + typeName
+}
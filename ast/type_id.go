@@ -7,13 +7,14 @@ import (
 
 // - TypeId
 //   ```
-//   [UnitId '.'] <type-identifier>
+//   [UnitId '.'] <type-identifier> [TypeArgs]
 //   ```
 type TypeId struct {
 	Type
-	UnitId *UnitId
-	Ident  *Ident
-	Ref    *astcore.Decl // Actual Type object
+	UnitId   *UnitId
+	Ident    *Ident
+	TypeArgs TypeArgs      // Specialization of generic type such as TList<Integer>
+	Ref      *astcore.Decl // Actual Type object
 }
 
 var _ Type = (*TypeId)(nil)
@@ -52,6 +53,9 @@ func (m *TypeId) Children() Nodes {
 		r = append(r, m.UnitId)
 	}
 	r = append(r, m.Ident)
+	if m.TypeArgs != nil {
+		r = append(r, m.TypeArgs)
+	}
 	return r
 }
 
@@ -1,14 +1,49 @@
 package ast
 
+import (
+	"strings"
+
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/pkg/errors"
+)
+
 type InterfaceType interface {
 	IsInterfaceType() bool
 	// implements
 	Type
 }
 
+// - ForwardDeclaredInterfaceType
+//   ```
+//   (INTERFACE | DISPINTERFACE)
+//   ```
+type ForwardDeclaredInterfaceType struct {
+	DispInterface bool
+	// This will be set at the end of actual interface type declaration.
+	Actual *CustomInterfaceType
+}
+
+func (m *ForwardDeclaredInterfaceType) SetActualType(t Type) error {
+	if intf, ok := t.(*CustomInterfaceType); ok {
+		m.Actual = intf
+		return nil
+	} else {
+		return errors.Errorf("%T is not a valid CustomInterfaceType", t)
+	}
+}
+
+var _ ForwardDeclaration = (*ForwardDeclaredInterfaceType)(nil)
+var _ InterfaceType = (*ForwardDeclaredInterfaceType)(nil)
+
+func (*ForwardDeclaredInterfaceType) isType()               {}
+func (*ForwardDeclaredInterfaceType) IsInterfaceType() bool { return true }
+func (m *ForwardDeclaredInterfaceType) Children() Nodes {
+	return Nodes{}
+}
+
 // - InterfaceType
 //   ```
-//   INTERFACE
+//   (INTERFACE | DISPINTERFACE)
 //   [InterfaceHeritage]
 //   [InterfaceGuid]
 //   [InterfaceMemberList]
@@ -16,9 +51,10 @@ type InterfaceType interface {
 //   END
 //   ```
 type CustomInterfaceType struct {
-	Heritage InterfaceHeritage
-	Guid     *InterfaceGuid
-	Members  InterfaceMemberList
+	DispInterface bool
+	Heritage      InterfaceHeritage
+	Guid          *InterfaceGuid
+	Members       InterfaceMemberList
 }
 
 var _ InterfaceType = (*CustomInterfaceType)(nil)
@@ -39,6 +75,58 @@ func (m *CustomInterfaceType) Children() Nodes {
 	return r
 }
 
+// GetParentInterfaces returns the interfaces in Heritage which are declared.
+func (m *CustomInterfaceType) GetParentInterfaces() []*CustomInterfaceType {
+	r := []*CustomInterfaceType{}
+	for _, typeId := range m.Heritage {
+		if typeId.Ref == nil {
+			continue
+		}
+		typeDecl, ok := typeId.Ref.Node.(*TypeDecl)
+		if !ok {
+			continue
+		}
+		switch v := typeDecl.Type.(type) {
+		case *CustomInterfaceType:
+			r = append(r, v)
+		case *ForwardDeclaredInterfaceType:
+			if v.Actual != nil {
+				r = append(r, v.Actual)
+			}
+		}
+	}
+	return r
+}
+
+// FindMemberDecl returns the declaration of the method or the property named name.
+// The members of the parent interfaces are also looked up.
+func (m *CustomInterfaceType) FindMemberDecl(name string) *astcore.Decl {
+	for _, member := range m.Members {
+		if decl := member.ToDeclarations().Find(name); decl != nil {
+			return decl
+		}
+	}
+	for _, parent := range m.GetParentInterfaces() {
+		if decl := parent.FindMemberDecl(name); decl != nil {
+			return decl
+		}
+	}
+	return nil
+}
+
+// MemberDecls returns the declarations of the members including inherited ones.
+// Inherited members come before the members of the interface.
+func (m *CustomInterfaceType) MemberDecls() astcore.Decls {
+	r := astcore.Decls{}
+	for _, parent := range m.GetParentInterfaces() {
+		r = append(r, parent.MemberDecls()...)
+	}
+	for _, member := range m.Members {
+		r = append(r, member.ToDeclarations()...)
+	}
+	return r
+}
+
 // - InterfaceHeritage
 //   ```
 // '(' TypeId ',' ... ')'
@@ -93,7 +181,7 @@ func (s InterfaceMemberList) Children() Nodes {
 //   InterfaceProperty
 //   ```
 type InterfaceMember interface {
-	Node
+	astcore.DeclNode
 	isInterfaceMember()
 }
 
@@ -104,12 +192,21 @@ type InterfaceMember interface {
 type InterfaceMethod struct {
 	Heading    InterfaceMethodHeading
 	Directives InterfaceMethodDirectives
+	DispId     *ConstExpr // only for dispinterface
 }
 
-var _ Node = (*InterfaceMethod)(nil)
+var _ InterfaceMember = (*InterfaceMethod)(nil)
 
+func (*InterfaceMethod) isInterfaceMember() {}
 func (m *InterfaceMethod) Children() Nodes {
-	return Nodes{m.Heading}
+	r := Nodes{m.Heading}
+	if m.DispId != nil {
+		r = append(r, m.DispId)
+	}
+	return r
+}
+func (m *InterfaceMethod) ToDeclarations() astcore.Decls {
+	return astcore.Decls{{Ident: m.Heading.GetIdent(), Node: m}}
 }
 
 // - InterfaceMethodHeading
@@ -121,6 +218,7 @@ func (m *InterfaceMethod) Children() Nodes {
 //   ```
 type InterfaceMethodHeading interface {
 	Node
+	GetIdent() *Ident
 	isInterfaceMethodHeading()
 }
 
@@ -128,27 +226,84 @@ type InterfaceMethodHeading interface {
 //   ```
 //   stdcall
 //   ```
+//   ```
+//   safecall
+//   ```
+//   ```
+//   cdecl
+//   ```
+//   ```
+//   register
+//   ```
+//   ```
+//   pascal
+//   ```
+//   ```
+//   overload
+//   ```
+//   ```
+//   dispid ConstExpr
+//   ```
 type InterfaceMethodDirective string
 
 const (
-	ImdStdcall InterfaceMethodDirective = "STDCALL"
+	ImdStdcall  InterfaceMethodDirective = "STDCALL"
+	ImdSafecall InterfaceMethodDirective = "SAFECALL"
+	ImdCdecl    InterfaceMethodDirective = "CDECL"
+	ImdRegister InterfaceMethodDirective = "REGISTER"
+	ImdPascal   InterfaceMethodDirective = "PASCAL"
+	ImdOverload InterfaceMethodDirective = "OVERLOAD"
+	ImdDispId   InterfaceMethodDirective = "DISPID"
 )
 
 type InterfaceMethodDirectives []InterfaceMethodDirective
 
+func (s InterfaceMethodDirectives) Include(w string) bool {
+	kw := InterfaceMethodDirective(strings.ToUpper(w))
+	for _, m := range s {
+		if m == kw {
+			return true
+		}
+	}
+	return false
+}
+
+var AllInterfaceMethodDirectives = InterfaceMethodDirectives{
+	ImdStdcall,
+	ImdSafecall,
+	ImdCdecl,
+	ImdRegister,
+	ImdPascal,
+	ImdOverload,
+	ImdDispId,
+}
+
 // - InterfaceProperty
 //   ```
-//   PROPERTY Ident PropertyInterface [READ Ident] [WRITE Ident]
+//   PROPERTY Ident PropertyInterface PropertyAccessor
+//   ```
+//   PropertyAccessor is
 //   ```
+//   [READ Ident] [WRITE Ident] [READONLY] [WRITEONLY] [DISPID ConstExpr] [';' DEFAULT]
+//   ```
+//   READONLY, WRITEONLY and DISPID are used in dispinterface.
 type InterfaceProperty struct {
 	Ident     *Ident
 	Interface *PropertyInterface
 	Read      *IdentRef
 	Write     *IdentRef
+	ReadOnly  bool
+	WriteOnly bool
+	DispId    *ConstExpr
+	Default   bool // default array property
 }
 
-var _ Node = (*InterfaceProperty)(nil)
+var _ InterfaceMember = (*InterfaceProperty)(nil)
 
+func (*InterfaceProperty) isInterfaceMember() {}
+func (m *InterfaceProperty) ToDeclarations() astcore.Decls {
+	return astcore.Decls{{Ident: m.Ident, Node: m}}
+}
 func (m *InterfaceProperty) Children() Nodes {
 	r := Nodes{m.Ident}
 	if m.Interface != nil {
@@ -160,5 +315,8 @@ func (m *InterfaceProperty) Children() Nodes {
 	if m.Write != nil {
 		r = append(r, m.Write)
 	}
+	if m.DispId != nil {
+		r = append(r, m.DispId)
+	}
 	return r
 }
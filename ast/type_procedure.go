@@ -5,10 +5,13 @@ package ast
 //   ```
 //   (ProcedureHeading | FunctionHeading) [OF OBJECT]
 //   ```
+//   ```
+//   REFERENCE TO (ProcedureHeading | FunctionHeading)
+//   ```
 //
 // Actual:
 // ```
-// (FUNCTION | PROCEDURE) [FormalParameters] [':' (TypeId)] [of object]
+// [REFERENCE TO] (FUNCTION | PROCEDURE) [FormalParameters] [':' (TypeId)] [of object]
 // ```
 
 type ProcedureType struct {
@@ -16,6 +19,7 @@ type ProcedureType struct {
 	FormalParameters FormalParameters
 	ReturnType       *TypeId
 	OfObject         bool
+	Reference        bool // method reference type declared with `reference to`
 }
 
 var _ Type = (*ProcedureType)(nil)
@@ -77,20 +77,60 @@ func (m *FileType) Children() Nodes {
 
 // - RecType
 //   ```
-//   RECORD [FieldList] END [PortabilityDirective]
+//   RECORD [FieldList] [ClassMemberSection ...] END [PortabilityDirective]
 //   ```
+// Members have the sections with visibility, methods, properties, operators
+// and nested declarations following the fields in FieldList.
 type RecType struct {
 	FieldList *FieldList
+	Members   ClassMemberSections
 	Packed    bool
 }
 
 var _ StrucType = (*RecType)(nil)
+var _ ClassMembersType = (*RecType)(nil)
 
 func (*RecType) isType()          {}
 func (*RecType) isStrucType()     {}
 func (m *RecType) IsPacked() bool { return m.Packed }
 func (m *RecType) Children() Nodes {
-	return Nodes{m.FieldList}
+	r := Nodes{m.FieldList}
+	if m.Members != nil {
+		r = append(r, m.Members)
+	}
+	return r
+}
+
+func (m *RecType) AddMemberSection(section *ClassMemberSection) {
+	m.Members = append(m.Members, section)
+}
+
+func (m *RecType) FindMemberDecl(name string, access *MemberAccess) *astcore.Decl {
+	if m.FieldList != nil {
+		if fieldDecl := m.FieldList.FindFieldDecl(name); fieldDecl != nil {
+			return fieldDecl.ToDeclarations().Find(name)
+		}
+	}
+	return m.Members.findMemberDecl(name, m, access)
+}
+
+func (m *RecType) FindProperty(name string, acendant bool) *ClassProperty {
+	return m.Members.findProperty(name)
+}
+
+func (m *RecType) FindMethods(name string) []*ClassMethod {
+	return m.Members.findMethods(name)
+}
+
+// MemberDecls returns the declarations of the fields in FieldList and the members.
+func (m *RecType) MemberDecls(access *MemberAccess) astcore.Decls {
+	r := astcore.Decls{}
+	if m.FieldList != nil {
+		for _, fieldDecl := range m.FieldList.AllFieldDecls() {
+			r = append(r, fieldDecl.ToDeclarations()...)
+		}
+	}
+	return append(r, m.Members.memberDecls(m, access)...)
 }
 
 // - FieldList
@@ -112,6 +152,29 @@ func (m *FieldList) Children() Nodes {
 	return r
 }
 
+// FindFieldDecl returns the field declaration including the fields in the variant section.
+func (m *FieldList) FindFieldDecl(name string) *FieldDecl {
+	for _, fieldDecl := range m.AllFieldDecls() {
+		if fieldDecl.IdentList.Find(name) != nil {
+			return fieldDecl
+		}
+	}
+	return nil
+}
+
+// AllFieldDecls returns FieldDecls and the field declarations in the variant section.
+func (m *FieldList) AllFieldDecls() FieldDecls {
+	r := append(FieldDecls{}, m.FieldDecls...)
+	if m.VariantSection != nil {
+		for _, variant := range m.VariantSection.RecVariants {
+			if variant.FieldList != nil {
+				r = append(r, variant.FieldList.AllFieldDecls()...)
+			}
+		}
+	}
+	return r
+}
+
 type FieldDecls []*FieldDecl
 
 var _ Node = (FieldDecls)(nil)
@@ -126,18 +189,23 @@ func (s FieldDecls) Children() Nodes {
 
 // - FieldDecl
 //   ```
-//   IdentList ':' Type [PortabilityDirective]
+//   [Attributes] IdentList ':' Type [PortabilityDirective]
 //   ```
 type FieldDecl struct {
-	IdentList IdentList
-	Type      Type
+	Attributes Attributes
+	IdentList  IdentList
+	Type       Type
 	//PortabilityDirective
 }
 
 var _ astcore.DeclNode = (*FieldDecl)(nil)
 
 func (m *FieldDecl) Children() Nodes {
-	return Nodes{m.IdentList, m.Type}
+	r := Nodes{}
+	if m.Attributes != nil {
+		r = append(r, m.Attributes)
+	}
+	return append(r, m.IdentList, m.Type)
 }
 
 func (m *FieldDecl) ToDeclarations() astcore.Decls {
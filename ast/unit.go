@@ -21,6 +21,7 @@ type Unit struct {
 	ImplementationSection *ImplementationSection
 	InitSection           *InitSection // optional
 	DeclMap               astcore.DeclMap
+	CompilerDirectives    CompilerDirectives
 
 	Namespace
 	Goal
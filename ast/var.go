@@ -43,6 +43,7 @@ type VarDecl struct {
 	Absolute             VarDeclAbsolute
 	ConstExpr            *ConstExpr
 	PortabilityDirective *PortabilityDirective
+	CompilerDirectives   CompilerDirectives // the compiler directives just before the declaration
 }
 
 var _ astcore.DeclNode = (*VarDecl)(nil)
@@ -4,8 +4,8 @@ go 1.16
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/philopon/go-toposort v0.0.0-20170620085441-9be86dbd762f // indirect
-	github.com/pkg/errors v0.9.1 // indirect
-	github.com/stretchr/testify v1.7.0 // indirect
-	golang.org/x/text v0.3.7 // indirect
+	github.com/philopon/go-toposort v0.0.0-20170620085441-9be86dbd762f
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.3.7
 )
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/token"
+)
+
+func (p *Parser) ParseAnonymousMethod() (*ast.AnonymousMethod, error) {
+	defer p.TraceMethod("Parser.ParseAnonymousMethod")()
+
+	res := &ast.AnonymousMethod{}
+	t0 := p.CurrentToken()
+	switch t0.Value() {
+	case "FUNCTION":
+		res.FunctionType = ast.FtFunction
+	case "PROCEDURE":
+		res.FunctionType = ast.FtProcedure
+	default:
+		return nil, p.TokenErrorf("expects FUNCTION or PROCEDURE, but got %s", t0)
+	}
+
+	outer := p.context.Clone()
+	if err := func() error {
+		defer p.context.StackDeclMap()()
+
+		if p.NextToken().Is(token.Symbol('(')) {
+			formalParameters, err := p.ParseFormalParameters('(', ')')
+			if err != nil {
+				return err
+			}
+			res.FormalParameters = formalParameters
+		}
+		if res.FunctionType == ast.FtFunction {
+			if _, err := p.Current(token.Symbol(':')); err != nil {
+				return err
+			}
+			p.NextToken()
+			typ, err := p.ParseTypeId()
+			if err != nil {
+				return err
+			}
+			res.ReturnType = typ
+		}
+		block, err := p.ParseBlock()
+		if err != nil {
+			return err
+		}
+		res.Block = block
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+
+	res.Captures = p.findCaptures(res.Block, outer)
+	return res, nil
+}
+
+// findCaptures returns the local variables, parameters and Self of the enclosing
+// routines which are visible in outer and referred in the block.
+func (p *Parser) findCaptures(block *ast.Block, outer Context) astcore.Decls {
+	if p.globalContext == nil {
+		// Variables outside of routines are global.
+		return nil
+	}
+	var res astcore.Decls
+	found := map[*astcore.Decl]bool{}
+	astcore.WalkDown(block, func(n astcore.Node) error {
+		ref, ok := n.(*ast.IdentRef)
+		if !ok || ref.Ref == nil || found[ref.Ref] {
+			return nil
+		}
+		switch ref.Ref.Node.(type) {
+		case *ast.VarDecl, *ast.FormalParm, *ast.Self:
+		default:
+			return nil
+		}
+		if outer.Get(ref.Name) != ref.Ref || p.globalContext.Get(ref.Name) == ref.Ref {
+			return nil
+		}
+		found[ref.Ref] = true
+		res = append(res, ref.Ref)
+		return nil
+	})
+	return res
+}
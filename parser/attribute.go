@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/token"
+)
+
+// ParseAttributes parses the attributes before declarations.
+// It returns nil if the current token doesn't start attributes.
+func (p *Parser) ParseAttributes() (ast.Attributes, error) {
+	defer p.TraceMethod("Parser.ParseAttributes")()
+
+	var res ast.Attributes
+	for p.CurrentToken().Is(token.Symbol('[')) {
+		p.NextToken()
+		if err := p.Until(token.Symbol(']'), token.Symbol(','), func() error {
+			attr, err := p.ParseAttribute()
+			if err != nil {
+				return err
+			}
+			res = append(res, attr)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := p.Current(token.Symbol(']')); err != nil {
+			return nil, err
+		}
+		p.NextToken()
+	}
+	return res, nil
+}
+
+func (p *Parser) ParseAttribute() (*ast.Attribute, error) {
+	defer p.TraceMethod("Parser.ParseAttribute")()
+
+	t, err := p.Current(token.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.Attribute{IdentRef: ast.NewIdentRef(p.NewIdent(t), p.findAttributeDecl(t.RawString()))}
+	if !p.NextToken().Is(token.Symbol('(')) {
+		return res, nil
+	}
+	if p.NextToken().Is(token.Symbol(')')) {
+		p.NextToken()
+		return res, nil
+	}
+	exprList, err := p.ParseExprList(token.Symbol(')'))
+	if err != nil {
+		return nil, err
+	}
+	res.ExprList = exprList
+	if _, err := p.Current(token.Symbol(')')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return res, nil
+}
+
+// findAttributeDecl returns the declaration of the attribute class.
+// [JSONName] refers JSONNameAttribute if it exists, otherwise JSONName.
+func (p *Parser) findAttributeDecl(name string) *astcore.Decl {
+	if decl := p.context.Get(name + "Attribute"); decl != nil {
+		return decl
+	}
+	return p.context.Get(name)
+}
+
+// tokenAfterAttributes returns the token following the attributes at the
+// current position without consuming them. It is used to decide which
+// declaration the attributes belong to.
+func (p *Parser) tokenAfterAttributes() *token.Token {
+	t := p.CurrentToken()
+	if !t.Is(token.Symbol('[')) {
+		return t
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	depth := 0
+	for t.Is(token.Symbol('[')) || depth > 0 {
+		if t.Is(token.EOF) {
+			return t
+		}
+		if t.Is(token.Symbol('[')) {
+			depth++
+		} else if t.Is(token.Symbol(']')) {
+			depth--
+		}
+		t = p.NextToken()
+	}
+	return t
+}
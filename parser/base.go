@@ -2,34 +2,71 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/log"
+	"github.com/akm/tparser/preprocessor"
 	"github.com/akm/tparser/token"
 	"github.com/pkg/errors"
 )
 
 type Parser struct {
-	tokenizer        *token.Tokenizer
+	tokenizer        *preprocessor.Preprocessor
 	curr             *token.Token
 	context          Context
 	postSectionFuncs []func()
+	// globalContext is the context outside of the routine being parsed.
+	// It is used to distinguish local variables from global ones.
+	globalContext Context
+	// declaredTypes are the types declared in the text being parsed.
+	// Their PRIVATE and PROTECTED members can be accessed anywhere in the text.
+	declaredTypes map[ast.ClassMembersType]bool
+	// compilerDirectives are the compiler directives by their tokens.
+	// They are shared by the goal and the nodes which they are attached to.
+	compilerDirectives map[*token.Token]*ast.CompilerDirective
 }
 
 func NewParser(ctx Context) *Parser {
 	if ctx == nil {
 		panic(errors.Errorf("context is required for NewParser"))
 	}
-	return &Parser{context: ctx}
+	return &Parser{
+		context:            ctx,
+		declaredTypes:      map[ast.ClassMembersType]bool{},
+		compilerDirectives: map[*token.Token]*ast.CompilerDirective{},
+	}
 }
 
 func (p *Parser) SetText(text *[]rune) {
-	p.tokenizer = token.NewTokenizer(text, 0)
+	flags := token.LoadComment
+	if p.context.IsLossless() {
+		flags |= token.LoadTrivia
+	}
+	p.tokenizer = preprocessor.NewPreprocessor(token.NewTokenizer(text, flags), p.context.GetDefines())
+	if constants := p.context.GetConstants(); constants != nil {
+		p.tokenizer.Constants = constants
+	}
+	p.tokenizer.Declared = func(name string) bool {
+		if p.context.Get(name) != nil {
+			return true
+		}
+		// Qualified identifiers such as System.TObject are looked up without the unit name.
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			return p.context.Get(name[i+1:]) != nil
+		}
+		return false
+	}
 }
 
+// RollbackPoint returns the function which restores the tokenizer, the current
+// token and the context. The context is restored as the same object, so that
+// UnitParser and ProgramParser keep sharing it with Parser. The scopes stacked
+// after the point are restored by the functions returned from StackDeclMap.
 func (p *Parser) RollbackPoint() func() {
 	tokenizer := p.tokenizer.Clone()
 	curr := p.curr.Clone()
-	ctx := p.context.Clone()
+	ctx := p.context
 	return func() {
 		p.tokenizer = tokenizer
 		p.curr = curr
@@ -37,6 +74,39 @@ func (p *Parser) RollbackPoint() func() {
 	}
 }
 
+// CompilerDirectives returns the compiler directives read so far.
+func (p *Parser) CompilerDirectives() ast.CompilerDirectives {
+	directives := p.tokenizer.Directives()
+	if len(directives) == 0 {
+		return nil
+	}
+	res := make(ast.CompilerDirectives, len(directives))
+	for i, d := range directives {
+		res[i] = p.compilerDirective(d.Token)
+	}
+	return res
+}
+
+// leadingCompilerDirectives returns the compiler directives just before the tokens.
+func (p *Parser) leadingCompilerDirectives(tokens ...*token.Token) ast.CompilerDirectives {
+	var res ast.CompilerDirectives
+	for _, t := range tokens {
+		for _, d := range t.LeadingDirectives {
+			res = append(res, p.compilerDirective(d))
+		}
+	}
+	return res
+}
+
+func (p *Parser) compilerDirective(t *token.Token) *ast.CompilerDirective {
+	if d, ok := p.compilerDirectives[t]; ok {
+		return d
+	}
+	d := ast.NewCompilerDirective(t)
+	p.compilerDirectives[t] = d
+	return d
+}
+
 func (p *Parser) NextToken() *token.Token {
 	p.curr = p.tokenizer.GetNext()
 	return p.curr
@@ -65,6 +135,11 @@ func (p *Parser) Validate(t *token.Token, predicates ...token.Predicator) error
 	if t == nil {
 		return errors.Errorf("something wrong, token is nil")
 	}
+	// The tokens after a conditional directive which can't be evaluated may
+	// not be the ones which the compiler reads.
+	if p.tokenizer != nil && p.tokenizer.Err() != nil {
+		return p.tokenizer.Err()
+	}
 	for _, pred := range predicates {
 		if !pred.Predicate(t) {
 			return p.TokenErrorf("expects "+pred.Name()+" but was %s", t)
@@ -122,7 +197,11 @@ func (p *Parser) TokenErrorf(format string, t *token.Token, args ...interface{})
 }
 
 func (p *Parser) PlaceString(t *token.Token) string {
-	return fmt.Sprintf("%s:%d:%d", p.context.GetPath(), t.Start.Line, t.Start.Col)
+	path := t.Start.Path
+	if path == "" {
+		path = p.context.GetPath()
+	}
+	return fmt.Sprintf("%s:%d:%d", path, t.Start.Line, t.Start.Col)
 }
 
 func (p *Parser) SetupPostSectionFuncs() func() {
@@ -7,16 +7,25 @@ import (
 
 func (p *Parser) ParseBlock() (*ast.Block, error) {
 	res := &ast.Block{}
-	if declSections, err := p.ParseDeclSections(); err != nil {
-		return nil, err
-	} else if declSections != nil {
-		res.DeclSections = declSections
-	}
+	// Exports statements can appear between declaration sections.
+	for {
+		declSections, err := p.ParseDeclSections()
+		if err != nil {
+			return nil, err
+		} else if declSections != nil {
+			res.DeclSections = append(res.DeclSections, declSections...)
+		}
 
-	if exportStmts, err := p.ParseExportsStmts(); err != nil {
-		return nil, err
-	} else if exportStmts != nil {
-		res.ExportsStmts1 = exportStmts
+		exportStmts, err := p.ParseExportsStmts()
+		if err != nil {
+			return nil, err
+		} else if exportStmts != nil {
+			res.ExportsStmts1 = append(res.ExportsStmts1, exportStmts...)
+		}
+
+		if declSections == nil && exportStmts == nil {
+			break
+		}
 	}
 
 	switch p.CurrentToken().Value() {
@@ -37,7 +46,7 @@ func (p *Parser) ParseBlock() (*ast.Block, error) {
 	if exportStmts, err := p.ParseExportsStmts(); err != nil {
 		return nil, err
 	} else if exportStmts != nil {
-		res.ExportsStmts1 = exportStmts
+		res.ExportsStmts2 = exportStmts
 	}
 
 	return res, nil
@@ -132,21 +141,29 @@ func (p *Parser) ParseExportsStmt(required bool) (*ast.ExportsStmt, error) {
 			return err
 		}
 		item := &ast.ExportsItem{Ident: p.NewIdent(t)}
-		t2 := p.NextToken()
-		if t2.Is(token.Directives("NAME")) {
-			p.NextToken()
-			constExpr, err := p.ParseConstExpr()
-			if err != nil {
-				return err
-			}
-			item.Name = constExpr
-		} else if t2.Is(token.Directives("INDEX")) {
-			p.NextToken()
-			constExpr, err := p.ParseConstExpr()
-			if err != nil {
-				return err
+		p.NextToken()
+		for {
+			t2 := p.CurrentToken()
+			if t2.Is(token.Directives("NAME")) && item.Name == nil {
+				p.NextToken()
+				constExpr, err := p.ParseConstExpr()
+				if err != nil {
+					return err
+				}
+				item.Name = constExpr
+			} else if t2.Is(token.Directives("INDEX")) && item.Index == nil {
+				p.NextToken()
+				constExpr, err := p.ParseConstExpr()
+				if err != nil {
+					return err
+				}
+				item.Index = constExpr
+			} else if t2.Is(token.Directives("RESIDENT")) && !item.Resident {
+				item.Resident = true
+				p.NextToken()
+			} else {
+				break
 			}
-			item.Index = constExpr
 		}
 		items = append(items, item)
 		return nil
@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/token"
+	"github.com/pkg/errors"
 )
 
 func (p *Parser) ParseConstSection(required bool) (ast.ConstSection, error) {
@@ -16,6 +19,7 @@ func (p *Parser) ParseConstSection(required bool) (ast.ConstSection, error) {
 			return nil, nil
 		}
 	}
+	kwToken := p.CurrentToken()
 	p.NextToken()
 	res := ast.ConstSection{}
 	for {
@@ -23,6 +27,10 @@ func (p *Parser) ParseConstSection(required bool) (ast.ConstSection, error) {
 		if err != nil {
 			return nil, err
 		}
+		if len(res) == 0 {
+			// The compiler directives before CONST are attached to the first declaration.
+			decl.CompilerDirectives = append(p.leadingCompilerDirectives(kwToken), decl.CompilerDirectives...)
+		}
 		if _, err := p.Current(token.Symbol(';')); err != nil {
 			return nil, err
 		}
@@ -36,7 +44,7 @@ func (p *Parser) ParseConstSection(required bool) (ast.ConstSection, error) {
 }
 
 func (p *Parser) ParseConstantDecl() (*ast.ConstantDecl, error) {
-	res := &ast.ConstantDecl{}
+	res := &ast.ConstantDecl{CompilerDirectives: p.leadingCompilerDirectives(p.CurrentToken())}
 	ident, err := p.Current(token.Some(token.Identifier, token.Directive))
 	if err != nil {
 		return nil, err
@@ -60,6 +68,18 @@ func (p *Parser) ParseConstantDecl() (*ast.ConstantDecl, error) {
 	}
 
 	p.NextToken()
+	if res.Type != nil {
+		typed, err := p.ParseTypedConstant(res.Type)
+		if err != nil {
+			return nil, err
+		}
+		if expr, ok := typed.(*ast.ConstExpr); ok {
+			res.ConstExpr = expr
+		} else {
+			res.TypedConstant = typed
+		}
+		return res, nil
+	}
 	expr, err := p.ParseConstExpr()
 	if err != nil {
 		return nil, err
@@ -69,6 +89,247 @@ func (p *Parser) ParseConstantDecl() (*ast.ConstantDecl, error) {
 	return res, nil
 }
 
+// ParseTypedConstant parses the value of a typed constant of typ.
+// When typ is unknown, '(' is parsed as an expression if possible,
+// otherwise as an ArrayConstant or a RecordConstant.
+func (p *Parser) ParseTypedConstant(typ ast.Type) (ast.TypedConstant, error) {
+	if !p.CurrentToken().Is(token.Symbol('(')) {
+		return p.ParseConstExpr()
+	}
+	switch t := actualType(typ).(type) {
+	case *ast.ArrayType:
+		return p.ParseArrayConstant(t)
+	case *ast.RecType:
+		return p.ParseRecordConstant(t)
+	case nil:
+		rollback := p.RollbackPoint()
+		if expr, err := p.ParseConstExpr(); err == nil && p.CurrentToken().Is(typedConstantBreak) {
+			return expr, nil
+		}
+		rollback()
+		if p.isRecordConstant() {
+			return p.ParseRecordConstant(nil)
+		}
+		return p.ParseArrayConstant(nil)
+	default:
+		return p.ParseConstExpr()
+	}
+}
+
+var typedConstantBreak = token.Some(
+	token.Symbol(';'),
+	token.Symbol(','),
+	token.Symbol(')'),
+)
+
+// isRecordConstant returns true if the tokens after '(' are Ident ':'.
+func (p *Parser) isRecordConstant() bool {
+	defer p.RollbackPoint()()
+	return p.NextToken().Is(token.Identifier) && p.NextToken().Is(token.Symbol(':'))
+}
+
+// ParseArrayConstant parses an ArrayConstant for arrayType.
+// arrayType can be nil if it is unknown.
+func (p *Parser) ParseArrayConstant(arrayType *ast.ArrayType) (ast.ArrayConstant, error) {
+	t0, err := p.Current(token.Symbol('('))
+	if err != nil {
+		return nil, err
+	}
+	var elementType ast.Type
+	expected := -1
+	if arrayType != nil {
+		elementType = arrayType.BaseType
+		if len(arrayType.IndexTypes) > 0 {
+			if n, ok := ordinalTypeLength(arrayType.IndexTypes[0]); ok {
+				expected = n
+			}
+			if len(arrayType.IndexTypes) > 1 {
+				// array[A, B] of T is the same as array[A] of array[B] of T
+				elementType = &ast.ArrayType{IndexTypes: arrayType.IndexTypes[1:], BaseType: arrayType.BaseType}
+			}
+		}
+	}
+
+	res := ast.ArrayConstant{}
+	p.NextToken()
+	if err := p.Until(token.Symbol(')'), token.Symbol(','), func() error {
+		elem, err := p.ParseTypedConstant(elementType)
+		if err != nil {
+			return err
+		}
+		res = append(res, elem)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	if expected >= 0 && expected != len(res) {
+		return nil, errors.Errorf("array constant expects %d elements but was %d at %s", expected, len(res), p.PlaceString(t0))
+	}
+	return res, nil
+}
+
+// ParseRecordConstant parses a RecordConstant for recType.
+// recType can be nil if it is unknown.
+func (p *Parser) ParseRecordConstant(recType *ast.RecType) (ast.RecordConstant, error) {
+	if _, err := p.Current(token.Symbol('(')); err != nil {
+		return nil, err
+	}
+	res := ast.RecordConstant{}
+	p.NextToken()
+	if err := p.Until(token.Symbol(')'), token.Symbol(';'), func() error {
+		if p.CurrentToken().Is(token.Symbol(')')) {
+			// The last field can be followed by ';'
+			return QuitUntil
+		}
+		t, err := p.Current(token.Identifier)
+		if err != nil {
+			return err
+		}
+		field := &ast.RecordFieldConstant{IdentRef: ast.NewIdentRef(p.NewIdent(t), nil)}
+		var fieldType ast.Type
+		if recType != nil {
+			fieldDecl := findRecordField(recType, t.RawString())
+			if fieldDecl == nil {
+				return p.TokenErrorf("unknown field %s", t)
+			}
+			field.IdentRef.Ref = fieldDecl.ToDeclarations().Find(t.RawString())
+			fieldType = fieldDecl.Type
+		}
+		if _, err := p.Next(token.Symbol(':')); err != nil {
+			return err
+		}
+		p.NextToken()
+		value, err := p.ParseTypedConstant(fieldType)
+		if err != nil {
+			return err
+		}
+		field.Value = value
+		res = append(res, field)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return res, nil
+}
+
+// actualType returns the type which typ refers through TypeIds.
+// It returns nil if the type is unknown.
+func actualType(typ ast.Type) ast.Type {
+	for {
+		typeId, ok := typ.(*ast.TypeId)
+		if !ok {
+			return typ
+		}
+		if typeId.Ref == nil {
+			return nil
+		}
+		decl, ok := typeId.Ref.Node.(*ast.TypeDecl)
+		if !ok || decl.Type == nil || decl.Type == typ {
+			return nil
+		}
+		typ = decl.Type
+	}
+}
+
+func findRecordField(recType *ast.RecType, name string) *ast.FieldDecl {
+	if recType.FieldList == nil {
+		return nil
+	}
+	return recType.FieldList.FindFieldDecl(name)
+}
+
+// ordinalTypeLengths are the numbers of values of the ordinal types whose length can be used as index types.
+// Char is not included because it is AnsiChar or WideChar depending on the compiler.
+var ordinalTypeLengths = map[string]int{
+	"BOOLEAN":  2,
+	"BYTEBOOL": 2,
+	"SHORTINT": 256,
+	"BYTE":     256,
+	"ANSICHAR": 256,
+}
+
+// ordinalTypeLength returns the number of values of typ if it can be determined.
+func ordinalTypeLength(typ ast.Type) (int, bool) {
+	if typeId, ok := typ.(*ast.TypeId); ok {
+		if n, ok := ordinalTypeLengths[strings.ToUpper(typeId.Ident.Name)]; ok {
+			return n, true
+		}
+	}
+	switch t := actualType(typ).(type) {
+	case ast.EnumeratedType:
+		for _, elem := range t {
+			if elem.ConstExpr != nil {
+				return 0, false
+			}
+		}
+		return len(t), true
+	case *ast.SubrangeType:
+		low, ok := ordinalValue(t.Low)
+		if !ok {
+			return 0, false
+		}
+		high, ok := ordinalValue(t.High)
+		if !ok || high < low {
+			return 0, false
+		}
+		return int(high-low) + 1, true
+	default:
+		return 0, false
+	}
+}
+
+// ordinalValue returns the value of expr if it is an integer, a character or a constant of them.
+func ordinalValue(expr *ast.ConstExpr) (int64, bool) {
+	if expr == nil || expr.SimpleExpression == nil || len(expr.RelOpSimpleExpressions) > 0 {
+		return 0, false
+	}
+	simpleExpr := expr.SimpleExpression
+	if simpleExpr.Term == nil || len(simpleExpr.AddOpTerms) > 0 || len(simpleExpr.Term.MulOpFactors) > 0 {
+		return 0, false
+	}
+	var r int64
+	switch f := simpleExpr.Term.Factor.(type) {
+	case *ast.NumberFactor:
+		// Real numerals and decimal integers beyond Int64 are not ordinal values.
+		if f.Radix == 10 && (strings.ContainsAny(f.Value, ".eE") || float64(f.Int) != f.Float) {
+			return 0, false
+		}
+		r = f.Int
+	case *ast.StringFactor:
+		runes := []rune(f.Decoded)
+		if len(runes) != 1 {
+			return 0, false
+		}
+		r = int64(runes[0])
+	case *ast.DesignatorFactor:
+		if f.ExprList != nil || len(f.Designator.Items) > 0 || f.Designator.QualId == nil || f.Designator.QualId.Ident == nil {
+			return 0, false
+		}
+		ref := f.Designator.QualId.Ident.Ref
+		if ref == nil {
+			return 0, false
+		}
+		constDecl, ok := ref.Node.(*ast.ConstantDecl)
+		if !ok || constDecl.ConstExpr == nil {
+			return 0, false
+		}
+		v, ok := ordinalValue(constDecl.ConstExpr)
+		if !ok {
+			return 0, false
+		}
+		r = v
+	default:
+		return 0, false
+	}
+	if simpleExpr.UnaryOp != nil && *simpleExpr.UnaryOp == "-" {
+		r = -r
+	}
+	return r, true
+}
+
 func (p *Parser) ParseConstExpr() (*ast.ConstExpr, error) {
 	// TODO Allow ConstExpr only
 	return p.ParseExpression()
@@ -9,12 +9,34 @@ type (
 	ProgramContext   = pcontext.ProgramContext
 	UnitContext      = pcontext.UnitContext
 	StackableContext = pcontext.StackableContext
+
+	UnitSearchPath    = pcontext.UnitSearchPath
+	UnitNotFoundError = pcontext.UnitNotFoundError
+
+	Encoding      = pcontext.Encoding
+	FileEncodings = pcontext.FileEncodings
+
+	Defines           = pcontext.Defines
+	Constants         = pcontext.Constants
+	IncludeSearchPath = pcontext.IncludeSearchPath
+	Lossless          = pcontext.Lossless
+)
+
+const (
+	ShiftJIS    = pcontext.ShiftJIS
+	Windows1252 = pcontext.Windows1252
+	UTF8        = pcontext.UTF8
+	UTF16LE     = pcontext.UTF16LE
+	UTF16BE     = pcontext.UTF16BE
 )
 
 var (
 	NewProgramContext   = pcontext.NewProgramContext
 	NewUnitContext      = pcontext.NewUnitContext
 	NewStackableContext = pcontext.NewStackableContext
+
+	NewDefines       = pcontext.NewDefines
+	DefaultConstants = pcontext.DefaultConstants
 )
 
 func NewContext(args ...interface{}) Context {
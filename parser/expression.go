@@ -147,6 +147,12 @@ func (p *Parser) ParseFactor() (ast.Factor, error) {
 	t0Value := t0.Value()
 	if t0.Is(token.SpecialSymbol) {
 		switch t0Value {
+		case "^":
+			// Control strings such as ^M after '=' are read as '^' and M by the tokenizer.
+			if s := token.JoinControlString(t0, p.NextToken()); s != nil {
+				return p.ParseStringFactor(s, true)
+			}
+			return nil, p.TokenErrorf("unexpected token %s", t0)
 		case "@":
 			p.NextToken()
 			d, err := p.ParseDesignator()
@@ -185,6 +191,8 @@ func (p *Parser) ParseFactor() (ast.Factor, error) {
 				return nil, err
 			}
 			return &ast.Not{Factor: f}, nil
+		case "PROCEDURE", "FUNCTION":
+			return p.ParseAnonymousMethod()
 		}
 	} else if ast.IsManifestConstant(t0Value) {
 		return p.ParseManifestConstant(t0, true)
@@ -196,10 +204,13 @@ func (p *Parser) ParseFactor() (ast.Factor, error) {
 
 		decl := p.context.Get(t0Value)
 		if decl != nil {
-			if _, ok := decl.Node.(*ast.TypeDecl); ok {
+			if typeDecl, ok := decl.Node.(*ast.TypeDecl); ok {
 				rollback := p.RollbackPoint()
 				typeId := ast.NewTypeId(ast.NewIdent(t0), decl)
 				p.NextToken()
+				if typeDecl.TypeParams != nil {
+					typeId.TypeArgs = p.parseTypeArgsInExpression()
+				}
 				if p.CurrentToken().Is(token.Symbol('(')) {
 					p.NextToken()
 					expr, err := p.ParseExpression()
@@ -251,16 +262,20 @@ func (p *Parser) ParseManifestConstant(t *token.Token, skipTypeCheck bool) (*ast
 func (p *Parser) ParseStringFactor(t *token.Token, skipTypeCheck bool) (*ast.StringFactor, error) {
 	if skipTypeCheck || t.Is(token.CharacterString) {
 		p.NextToken()
-		return &ast.StringFactor{Value: t.Value()}, nil
+		return ast.NewString(t.Value()), nil
 	} else {
 		return nil, p.TokenErrorf("unexpected token %s for StringFactor", t)
 	}
 }
 
 func (p *Parser) ParseNumberFactor(t *token.Token, skipTypeCheck bool) (*ast.NumberFactor, error) {
-	if skipTypeCheck || t.Is(token.CharacterString) {
+	if skipTypeCheck || t.Is(token.Some(token.NumeralInt, token.NumeralReal)) {
+		radix, i, f, err := token.ParseNumeral(t.Value())
+		if err != nil {
+			return nil, p.TokenErrorf("invalid number %s", t)
+		}
 		p.NextToken()
-		return &ast.NumberFactor{Value: t.Value()}, nil
+		return &ast.NumberFactor{Value: t.Value(), Radix: radix, Int: i, Float: f}, nil
 	} else {
 		return nil, p.TokenErrorf("unexpected token %s for NumberFactor", t)
 	}
@@ -278,6 +293,8 @@ func (p *Parser) ParseDesignator() (*ast.Designator, error) {
 	}
 	res.QualId = qualId
 
+	// typ is the type of the designator parsed so far, which is used to resolve members.
+	typ := declTypeOf(qualId.Ident.Ref)
 	for {
 		if _, err := p.Current(token.SpecialSymbol); err != nil {
 			break
@@ -289,7 +306,10 @@ func (p *Parser) ParseDesignator() (*ast.Designator, error) {
 			if err != nil {
 				return nil, err
 			}
-			item = ast.NewDesignatorItemIdent(t)
+			itemIdent := ast.NewDesignatorItemIdent(t)
+			itemIdent.Ref = p.findMemberDecl(typ, itemIdent.Name)
+			typ = declTypeOf(itemIdent.Ref)
+			item = itemIdent
 		case "[":
 			p.NextToken()
 			exprList, err := p.ParseExprList(token.Symbol(']'))
@@ -297,8 +317,26 @@ func (p *Parser) ParseDesignator() (*ast.Designator, error) {
 				return nil, err
 			}
 			item = ast.DesignatorItemExprList(exprList)
+			if arrayType, ok := actualType(typ).(*ast.ArrayType); ok {
+				typ = arrayType.BaseType
+			} else if !isIndexedProperty(res.Items) {
+				typ = nil
+			}
 		case "^":
 			item = &ast.DesignatorItemDereference{}
+			if pointerType, ok := actualType(typ).(*ast.CustomPointerType); ok {
+				typ = pointerType.TypeId
+			} else {
+				typ = nil
+			}
+		case "<":
+			if canBeSpecialized(res) {
+				if typeArgs := p.parseTypeArgsInExpression(); typeArgs != nil {
+					res.Items = append(res.Items, ast.DesignatorItemTypeArgs(typeArgs))
+					typ = nil
+					continue
+				}
+			}
 		}
 		if item == nil {
 			break
@@ -309,6 +347,30 @@ func (p *Parser) ParseDesignator() (*ast.Designator, error) {
 	return res, nil
 }
 
+// canBeSpecialized returns true if the last part of the designator can be
+// a generic type or a generic method.
+func canBeSpecialized(d *ast.Designator) bool {
+	if len(d.Items) == 0 {
+		return isGenericDecl(d.QualId.Ident.Ref)
+	}
+	_, ok := d.Items[len(d.Items)-1].(*ast.DesignatorItemIdent)
+	return ok
+}
+
+// isIndexedProperty returns true if the last item of the designator is an array property
+// whose type is the type of the element.
+func isIndexedProperty(items ast.DesignatorItems) bool {
+	if len(items) == 0 {
+		return false
+	}
+	itemIdent, ok := items[len(items)-1].(*ast.DesignatorItemIdent)
+	if !ok || itemIdent.Ref == nil {
+		return false
+	}
+	prop, ok := itemIdent.Ref.Node.(*ast.ClassProperty)
+	return ok && prop.Interface != nil && prop.Interface.Parameters != nil
+}
+
 func (p *Parser) ParseSetConstructor() (*ast.SetConstructor, error) {
 	if _, err := p.Current(token.Symbol('[')); err != nil {
 		return nil, err
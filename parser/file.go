@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/token"
+)
+
+type File struct {
+	Goal ast.Goal
+	// Units are all the units loaded to parse Goal.
+	// When Goal is a unit, Units also include it.
+	Units        ast.Units
+	MissingUnits []*UnitNotFoundError
+}
+
+// ParseFile parses the program, library, package or unit file at path and the
+// units used by it. The kind of the goal is detected by the first reserved word.
+// args are passed to NewProgramContext like ParseProgram.
+func ParseFile(path string, args ...interface{}) (*File, error) {
+	ctx := NewProgramContext(append([]interface{}{path}, args...)...)
+	runes, err := readSourceFile(path, ctx.EncodingFor(path))
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewProgramParser(ctx)
+	p.SetText(&runes)
+	p.setupIncludes(ctx)
+	t := p.NextToken()
+
+	var goal ast.Goal
+	switch {
+	case t.Is(token.ReservedWord.HasKeyword("PROGRAM")):
+		goal, err = p.ParseProgram()
+	case t.Is(token.ReservedWord.HasKeyword("LIBRARY")):
+		goal, err = (&LibraryParser{ProgramParser: p}).ParseLibrary()
+	case t.Is(token.UpperCase("PACKAGE")):
+		goal, err = (&PackageParser{ProgramParser: p}).ParsePackage()
+	case t.Is(token.ReservedWord.HasKeyword("UNIT")):
+		ident, err := p.Next(token.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		goal, err = p.loadUnitGoal(ident.RawString(), path)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.TokenErrorf("expects PROGRAM, LIBRARY, PACKAGE or UNIT but was %s", t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &File{
+		Goal:         goal,
+		Units:        ctx.Units,
+		MissingUnits: ctx.MissingUnits,
+	}, nil
+}
+
+// loadUnitGoal loads the unit named name at path and the units used by it
+// in the same way as the units used by a program. name must be the one
+// declared in the unit heading, which can be different from the file name.
+func (p *ProgramParser) loadUnitGoal(name, path string) (*ast.Unit, error) {
+	quotedPath := "'" + path + "'"
+	item := &ast.UsesClauseItem{Ident: &ast.Ident{Name: name}, Path: &quotedPath}
+	if err := p.LoadUnits(ast.UsesClause{item}); err != nil {
+		return nil, err
+	}
+	return item.Unit, nil
+}
@@ -1,33 +1,117 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
 )
 
 func (p *Parser) ParseProcedureDeclSection() (*ast.FunctionDecl, error) {
-	var functionHeading *ast.FunctionHeading
-	switch p.CurrentToken().Value() {
-	case "PROCEDURE", "FUNCTION":
-		defer p.context.StackDeclMap()()
-		var err error
-		functionHeading, err = p.ParseFunctionHeading()
+	res := &ast.FunctionDecl{FunctionHeading: &ast.FunctionHeading{}}
+
+	t0 := p.CurrentToken()
+	res.CompilerDirectives = p.leadingCompilerDirectives(t0)
+	if t0.Is(token.ReservedWord.HasKeyword("CLASS")) {
+		res.ClassMethod = true
+		t0 = p.NextToken()
+	}
+	switch t0.Value() {
+	case "PROCEDURE":
+		res.Type = ast.FtProcedure
+	case "FUNCTION":
+		res.Type = ast.FtFunction
+	case "CONSTRUCTOR":
+		res.Type = ast.FtConstructor
+	case "DESTRUCTOR":
+		res.Type = ast.FtDestructor
+	default:
+		if !res.ClassMethod {
+			return nil, nil
+		}
+		if !t0.Is(token.UpperCase("OPERATOR")) {
+			return nil, p.TokenErrorf("expects PROCEDURE, FUNCTION or OPERATOR after CLASS, but got %s", t0)
+		}
+		res.Type = ast.FtOperator
+	}
+
+	if p.globalContext == nil {
+		p.globalContext = p.context.Clone()
+		defer func() { p.globalContext = nil }()
+	}
+	defer p.context.StackDeclMap()()
+
+	idents := []*ast.Ident{}
+	typeParamsList := []ast.TypeParams{}
+	for {
+		t, err := p.Next(token.Identifier)
 		if err != nil {
 			return nil, err
 		}
-	default:
-		return nil, nil
+		idents = append(idents, p.NewIdent(t))
+		var typeParams ast.TypeParams
+		if p.NextToken().Is(token.Symbol('<')) {
+			if typeParams, err = p.ParseTypeParams(); err != nil {
+				return nil, err
+			}
+		}
+		typeParamsList = append(typeParamsList, typeParams)
+		if !p.CurrentToken().Is(token.Symbol('.')) {
+			break
+		}
+	}
+	res.Ident = idents[len(idents)-1]
+	res.TypeParams = typeParamsList[len(typeParamsList)-1]
+
+	var classType ast.ClassMembersType
+	if len(idents) > 1 {
+		classType = p.setupMethodScope(res, idents[:len(idents)-1])
+		p.useClassTypeParams(res.ClassTypes, typeParamsList[:len(typeParamsList)-1])
+		// Parameters and local declarations can hide class members.
+		defer p.context.StackDeclMap()()
+	} else if res.ClassMethod || res.Type == ast.FtConstructor || res.Type == ast.FtDestructor {
+		return nil, p.TokenErrorf("%s requires class type before %s", t0, res.Ident.Name)
+	}
+
+	if res.IsMethod() && p.CurrentToken().Is(token.Symbol(';')) {
+		// Parameters and return type can be omitted in method implementation.
+		if classType != nil {
+			res.Method = findImplementedMethod(classType, res)
+		}
+		if res.Method != nil {
+			if heading, ok := res.Method.Heading.(*ast.FunctionHeading); ok {
+				res.ReturnType = heading.ReturnType
+			}
+			for _, parm := range methodFormalParameters(res.Method) {
+				if err := p.context.Set(parm); err != nil {
+					return nil, err
+				}
+			}
+		}
+	} else {
+		if err := p.parseFunctionHeadingSignature(res.FunctionHeading); err != nil {
+			return nil, err
+		}
+		if classType != nil {
+			res.Method = findImplementedMethod(classType, res)
+		}
+	}
+	if classType != nil && res.Method == nil {
+		p.Logf("method %s is not declared in %s", res.Ident.Name, res.ClassTypes[len(res.ClassTypes)-1].Name)
 	}
+
 	if _, err := p.Current(token.Symbol(';')); err != nil {
 		return nil, err
 	}
-	res := &ast.FunctionDecl{FunctionHeading: functionHeading}
-	if err := p.context.Set(res); err != nil {
-		return nil, err
+	if !res.IsMethod() {
+		if err := p.context.Set(res); err != nil {
+			return nil, err
+		}
 	}
 
 	p.NextToken()
-	if p.CurrentToken().Is(token.Directive) {
+	if p.CurrentToken().Is(p.contextualKeyword(token.Directive)) {
 		directives, opts, err := p.ParseFunctionDirectives()
 		if err != nil {
 			return nil, err
@@ -43,3 +127,160 @@ func (p *Parser) ParseProcedureDeclSection() (*ast.FunctionDecl, error) {
 	res.Block = block
 	return res, nil
 }
+
+// setupMethodScope resolves the class types of the method implementation and
+// puts the members of the class and Self into the current scope.
+// It returns nil if the class type is not found.
+func (p *Parser) setupMethodScope(res *ast.FunctionDecl, classIdents []*ast.Ident) ast.ClassMembersType {
+	res.ClassTypes = make([]*ast.IdentRef, len(classIdents))
+
+	var typeDecl *ast.TypeDecl
+	var classType ast.ClassMembersType
+	for i, ident := range classIdents {
+		var decl *astcore.Decl
+		if classType == nil {
+			if i == 0 {
+				decl = p.context.Get(ident.Name)
+			}
+		} else {
+			decl = classType.FindMemberDecl(ident.Name, p.memberAccess(classType))
+		}
+		res.ClassTypes[i] = ast.NewIdentRef(ident, decl)
+
+		typeDecl, classType = nil, nil
+		if decl != nil {
+			if d, ok := decl.Node.(*ast.TypeDecl); ok {
+				typeDecl = d
+				classType = classMembersTypeOf(d.Type)
+			}
+		}
+	}
+	if classType == nil {
+		p.Logf("class type for method %s is not found", res.Ident.Name)
+		return nil
+	}
+
+	for _, decl := range classType.MemberDecls(p.memberAccess(classType)) {
+		p.context.Overwrite(decl.Ident.Name, decl)
+	}
+	self := ast.NewSelf(typeDecl)
+	p.context.Overwrite(self.Ident.Name, self.ToDeclarations()[0])
+	return classType
+}
+
+// useClassTypeParams makes the type parameters of the class types in the method
+// implementation such as T of TFoo<T>.Bar refer the ones in the class type declaration.
+func (p *Parser) useClassTypeParams(classTypes []*ast.IdentRef, typeParamsList []ast.TypeParams) {
+	for i, classType := range classTypes {
+		if classType.Ref == nil || typeParamsList[i] == nil {
+			continue
+		}
+		typeDecl, ok := classType.Ref.Node.(*ast.TypeDecl)
+		if !ok || len(typeDecl.TypeParams) != len(typeParamsList[i]) {
+			continue
+		}
+		for j, param := range typeParamsList[i] {
+			p.context.Overwrite(param.Ident.Name, typeDecl.TypeParams[j].ToDeclarations()[0])
+		}
+	}
+}
+
+func classMembersTypeOf(typ ast.Type) ast.ClassMembersType {
+	switch v := typ.(type) {
+	case *ast.CustomClassType:
+		return v
+	case *ast.ForwardDeclaredClassType:
+		if v.Actual == nil {
+			return nil
+		}
+		return v.Actual
+	case *ast.CustomObjectType:
+		return v
+	case *ast.RecType:
+		return v
+	case *ast.HelperType:
+		return v
+	default:
+		return nil
+	}
+}
+
+// findImplementedMethod returns the method in classType which res implements.
+// Overloaded methods are distinguished by the number of parameters and the
+// type identifiers of them. It returns nil if the method can't be determined.
+func findImplementedMethod(classType ast.ClassMembersType, res *ast.FunctionDecl) *ast.ClassMethod {
+	methods := classType.FindMethods(res.Ident.Name)
+	switch len(methods) {
+	case 0:
+		return nil
+	case 1:
+		return methods[0]
+	}
+	types := parameterTypeNames(res.FormalParameters)
+	var found *ast.ClassMethod
+	for _, method := range methods {
+		if !sameParameterTypeNames(parameterTypeNames(methodFormalParameters(method)), types) {
+			continue
+		}
+		if found != nil {
+			// Ambiguous
+			return nil
+		}
+		found = method
+	}
+	return found
+}
+
+func methodFormalParameters(method *ast.ClassMethod) ast.FormalParameters {
+	switch v := method.Heading.(type) {
+	case *ast.FunctionHeading:
+		return v.FormalParameters
+	case *ast.ConstructorHeading:
+		return v.FormalParameters
+	case *ast.OperatorHeading:
+		return v.FormalParameters
+	default:
+		return nil
+	}
+}
+
+// parameterTypeNames returns the upper case type identifiers of each parameter.
+// The names of untyped parameters are empty, and the ones of array parameters
+// start with "ARRAY OF ".
+func parameterTypeNames(params ast.FormalParameters) []string {
+	r := []string{}
+	for _, parm := range params {
+		name := ""
+		if parm.Type != nil {
+			if typeId, ok := parm.Type.Type.(*ast.TypeId); ok {
+				name = strings.ToUpper(typeId.Ident.Name)
+			} else if parm.Type.Type != nil {
+				name = "?"
+			}
+			if parm.Type.IsArray {
+				name = "ARRAY OF " + name
+			}
+		}
+		for range parm.IdentList {
+			r = append(r, name)
+		}
+	}
+	return r
+}
+
+// sameParameterTypeNames returns true if a and b have the same number of
+// parameters and their types can't be distinguished.
+func sameParameterTypeNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == "?" || b[i] == "?" {
+			continue
+		}
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -30,7 +30,7 @@ func (p *Parser) ParseExportedHeading() (*ast.ExportedHeading, error) {
 	}
 
 	p.NextToken()
-	if p.CurrentToken().Is(token.Directive) {
+	if p.CurrentToken().Is(p.contextualKeyword(token.Directive)) {
 		directives, opts, err := p.ParseFunctionDirectives()
 		if err != nil {
 			return nil, err
@@ -46,7 +46,7 @@ func (p *Parser) ParseFunctionDirectives() ([]ast.Directive, *ast.ExternalOption
 	var opts *ast.ExternalOptions
 	for {
 		t := p.CurrentToken()
-		if !t.Is(token.Directive) {
+		if !t.Is(p.contextualKeyword(token.Directive)) {
 			break
 		}
 		dir := ast.Directive(strings.ToUpper(t.Value()))
@@ -137,26 +137,40 @@ func (p *Parser) ParseFunctionHeading() (*ast.FunctionHeading, error) {
 
 	defer p.TraceMethod("Parser.ParseFunctionHeading")()
 
-	t := p.NextToken()
-	if t.Is(token.Symbol('(')) {
-		formalParameters, err := p.ParseFormalParameters('(', ')')
+	if p.NextToken().Is(token.Symbol('<')) {
+		typeParams, err := p.ParseTypeParams()
 		if err != nil {
 			return nil, err
 		}
+		res.TypeParams = typeParams
+	}
+	if err := p.parseFunctionHeadingSignature(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseFunctionHeadingSignature parses FormalParameters and the return type after the identifier.
+func (p *Parser) parseFunctionHeadingSignature(res *ast.FunctionHeading) error {
+	if p.CurrentToken().Is(token.Symbol('(')) {
+		formalParameters, err := p.ParseFormalParameters('(', ')')
+		if err != nil {
+			return err
+		}
 		res.FormalParameters = formalParameters
 	}
-	if res.Type == ast.FtFunction {
+	if res.Type == ast.FtFunction || res.Type == ast.FtOperator {
 		if _, err := p.Current(token.Symbol(':')); err != nil {
-			return nil, err
+			return err
 		}
 		p.NextToken()
 		typ, err := p.ParseTypeId()
 		if err != nil {
-			return nil, err
+			return err
 		}
 		res.ReturnType = typ
 	}
-	return res, nil
+	return nil
 }
 
 func (p *Parser) ParseFormalParameters(startRune, endRune rune) (ast.FormalParameters, error) {
@@ -183,7 +197,11 @@ func (p *Parser) ParseFormalParameters(startRune, endRune rune) (ast.FormalParam
 }
 
 func (p *Parser) ParseFormalParm(endRune rune) (*ast.FormalParm, error) {
-	r := &ast.FormalParm{}
+	attrs, err := p.ParseAttributes()
+	if err != nil {
+		return nil, err
+	}
+	r := &ast.FormalParm{Attributes: attrs}
 	t := p.CurrentToken()
 	if t.Is(token.ReservedWord) {
 		switch t.Value() {
@@ -197,6 +215,12 @@ func (p *Parser) ParseFormalParm(endRune rune) (*ast.FormalParm, error) {
 			return nil, p.TokenErrorf("unexpected token %s", t)
 		}
 		p.NextToken()
+		// Attributes can follow the option like `const [Ref] A: Integer`.
+		if attrs, err := p.ParseAttributes(); err != nil {
+			return nil, err
+		} else if attrs != nil {
+			r.Attributes = append(r.Attributes, attrs...)
+		}
 	}
 	parameter, err := p.ParseParameter(endRune)
 	if err != nil {
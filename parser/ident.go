@@ -12,3 +12,35 @@ func (p *Parser) NewIdent(t *token.Token) *ast.Ident {
 func (p *Parser) NewIdentRef(t *token.Token) *ast.IdentRef {
 	return ast.NewIdentRef(p.NewIdent(t), p.context.Get(t.RawString()))
 }
+
+// identFollowers are the symbols which follow identifiers in declarations
+// such as `Name: string`, `Name, Index: Integer` or `Default = 0`.
+var identFollowers = token.Some(token.Symbol(':'), token.Symbol(','), token.Symbol('='))
+
+// isUsedAsIdent returns true if the current token is followed by a symbol
+// which means that it is declared as an identifier.
+func (p *Parser) isUsedAsIdent() bool {
+	rollback := p.RollbackPoint()
+	defer rollback()
+	return p.NextToken().Is(identFollowers)
+}
+
+// contextualKeyword is a Predicator for the words which are not reserved words
+// but keywords in some contexts, such as PUBLIC or HELPER. They don't match
+// the current token if it is declared as an identifier like `Public: Boolean;`.
+type contextualKeyword struct {
+	token.Predicator
+	parser *Parser
+}
+
+func (p *Parser) contextualKeyword(pred token.Predicator) token.Predicator {
+	return &contextualKeyword{Predicator: pred, parser: p}
+}
+
+func (k *contextualKeyword) Predicate(t *token.Token) bool {
+	if !k.Predicator.Predicate(t) {
+		return false
+	}
+	// Only the current token can be looked ahead.
+	return t != k.parser.curr || !k.parser.isUsedAsIdent()
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/token"
+)
+
+type Library struct {
+	*ast.Library
+	Units        ast.Units
+	MissingUnits []*UnitNotFoundError
+}
+
+// ParseLibrary parses the library file and the units used by it.
+// args are passed to NewProgramContext like ParseProgram.
+func ParseLibrary(path string, args ...interface{}) (*Library, error) {
+	ctx := NewProgramContext(append([]interface{}{path}, args...)...)
+	runes, err := readSourceFile(path, ctx.EncodingFor(path))
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewLibraryParser(ctx)
+	p.SetText(&runes)
+	p.setupIncludes(ctx)
+	p.NextToken()
+	res, err := p.ParseLibrary()
+	if err != nil {
+		return nil, err
+	}
+	return &Library{
+		Library:      res,
+		Units:        ctx.Units,
+		MissingUnits: ctx.MissingUnits,
+	}, nil
+}
+
+// LibraryParser parses a library. It loads the units used by the library
+// in the same way as ProgramParser.
+type LibraryParser struct {
+	*ProgramParser
+	Library *ast.Library
+}
+
+func NewLibraryParser(ctx *ProgramContext) *LibraryParser {
+	return &LibraryParser{ProgramParser: NewProgramParser(ctx)}
+}
+
+func (p *LibraryParser) ParseLibrary() (*ast.Library, error) {
+	if _, err := p.Current(token.ReservedWord.HasKeyword("LIBRARY")); err != nil {
+		return nil, err
+	}
+	ident, err := p.Next(token.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.Library{
+		Path:  p.context.GetPath(),
+		Ident: p.NewIdent(ident),
+	}
+	p.Library = res
+	p.goal = res
+	if _, err := p.Next(token.Symbol(';')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	block, err := p.ParseProgramBlock()
+	if err != nil {
+		return nil, err
+	}
+	res.ProgramBlock = block
+	if _, err := p.Current(token.Symbol('.')); err != nil {
+		return nil, err
+	}
+	res.CompilerDirectives = p.CompilerDirectives()
+	return res, nil
+}
@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/token"
+	"github.com/pkg/errors"
+)
+
+type Package struct {
+	*ast.Package
+	Units        ast.Units
+	MissingUnits []*UnitNotFoundError
+	// EffectiveUnitSearchPath is used to find the packages in RequiresClause.
+	EffectiveUnitSearchPath UnitSearchPath
+}
+
+// ParsePackage parses the package file and the units contained by it.
+// Packages in RequiresClause are not loaded. Use LoadPackageGraph to load them.
+// args are passed to NewProgramContext like ParseProgram.
+func ParsePackage(path string, args ...interface{}) (*Package, error) {
+	ctx := NewProgramContext(append([]interface{}{path}, args...)...)
+	runes, err := readSourceFile(path, ctx.EncodingFor(path))
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPackageParser(ctx)
+	p.SetText(&runes)
+	p.setupIncludes(ctx)
+	p.NextToken()
+	res, err := p.ParsePackage()
+	if err != nil {
+		return nil, err
+	}
+	return &Package{
+		Package:                 res,
+		Units:                   ctx.Units,
+		MissingUnits:            ctx.MissingUnits,
+		EffectiveUnitSearchPath: ctx.EffectiveUnitSearchPath(),
+	}, nil
+}
+
+// PackageParser parses a package. It loads the units contained by the package
+// in the same way as ProgramParser loads the units used by a program.
+type PackageParser struct {
+	*ProgramParser
+	Package *ast.Package
+}
+
+func NewPackageParser(ctx *ProgramContext) *PackageParser {
+	return &PackageParser{ProgramParser: NewProgramParser(ctx)}
+}
+
+func (p *PackageParser) ParsePackage() (*ast.Package, error) {
+	if _, err := p.Current(token.UpperCase("PACKAGE")); err != nil {
+		return nil, err
+	}
+	ident, err := p.Next(token.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.Package{
+		Path:  p.context.GetPath(),
+		Ident: p.NewIdent(ident),
+	}
+	p.Package = res
+	p.goal = res
+	if _, err := p.Next(token.Symbol(';')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	if p.CurrentToken().Is(token.UpperCase("REQUIRES")) {
+		requires, err := p.ParseRequiresClause()
+		if err != nil {
+			return nil, err
+		}
+		res.RequiresClause = requires
+		p.NextToken()
+	}
+
+	if p.CurrentToken().Is(token.UpperCase("CONTAINS")) {
+		contains, err := p.ParseContainsClause()
+		if err != nil {
+			return nil, err
+		}
+		res.ContainsClause = contains
+		p.NextToken()
+
+		if err := p.LoadUnits(ast.UsesClause(contains)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.Current(token.ReservedWord.HasKeyword("END")); err != nil {
+		return nil, err
+	}
+	if _, err := p.Next(token.Symbol('.')); err != nil {
+		return nil, err
+	}
+	res.CompilerDirectives = p.CompilerDirectives()
+	return res, nil
+}
+
+func (p *Parser) ParseRequiresClause() (ast.RequiresClause, error) {
+	if _, err := p.Current(token.UpperCase("REQUIRES")); err != nil {
+		return nil, err
+	}
+	r := ast.RequiresClause{}
+	p.NextToken()
+	if err := p.Until(token.Symbol(';'), token.Symbol(','), func() error {
+		t, err := p.Current(token.Identifier)
+		if err != nil {
+			return err
+		}
+		r = append(r, &ast.RequiresClauseItem{Ident: p.NewIdent(t)})
+		p.NextToken()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (p *Parser) ParseContainsClause() (ast.ContainsClause, error) {
+	if _, err := p.Current(token.UpperCase("CONTAINS")); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	items, err := p.parseUsesClauseItems()
+	if err != nil {
+		return nil, err
+	}
+	return ast.ContainsClause(items), nil
+}
+
+// PackageGraph is the dependency graph of packages across RequiresClause.
+type PackageGraph struct {
+	// Packages are sorted so that required packages come before the packages requiring them.
+	Packages        []*Package
+	MissingPackages []*PackageNotFoundError
+}
+
+// LoadPackageGraph parses the package at path and the packages required by it recursively.
+// Required packages are looked up as `<name>.dpk` in the directory of the requiring package
+// and its UnitSearchPath. args are passed to NewProgramContext for each package.
+func LoadPackageGraph(path string, args ...interface{}) (*PackageGraph, error) {
+	g := &PackageGraph{}
+	loading := []string{}
+	var load func(path string) (*Package, error)
+	load = func(path string) (*Package, error) {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		for _, i := range loading {
+			if strings.EqualFold(i, name) {
+				return nil, errors.Errorf("circular package reference: %s -> %s", strings.Join(loading, " -> "), name)
+			}
+		}
+		loading = append(loading, name)
+		defer func() { loading = loading[:len(loading)-1] }()
+
+		pkg, err := ParsePackage(path, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range pkg.RequiresClause {
+			if required := g.Find(item.Ident.Name); required != nil {
+				item.Package = required.Package
+				continue
+			}
+			if g.isMissing(item.Ident.Name) {
+				continue
+			}
+			requiredPath, err := pkg.EffectiveUnitSearchPath.FindFile(item.Ident.Name + ".dpk")
+			if err != nil {
+				return nil, err
+			}
+			if requiredPath == "" {
+				g.MissingPackages = append(g.MissingPackages, &PackageNotFoundError{
+					PackageName:  item.Ident.Name,
+					SearchedDirs: pkg.EffectiveUnitSearchPath,
+				})
+				continue
+			}
+			required, err := load(requiredPath)
+			if err != nil {
+				return nil, err
+			}
+			item.Package = required.Package
+		}
+		g.Packages = append(g.Packages, pkg)
+		return pkg, nil
+	}
+	if _, err := load(path); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Find returns the package which has the name.
+func (g *PackageGraph) Find(name string) *Package {
+	for _, pkg := range g.Packages {
+		if strings.EqualFold(pkg.Ident.Name, name) {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// Requirements returns the packages required by the package which has the name directly.
+func (g *PackageGraph) Requirements(name string) []*Package {
+	pkg := g.Find(name)
+	if pkg == nil {
+		return nil
+	}
+	r := []*Package{}
+	for _, item := range pkg.RequiresClause {
+		if required := g.Find(item.Ident.Name); required != nil {
+			r = append(r, required)
+		}
+	}
+	return r
+}
+
+func (g *PackageGraph) isMissing(name string) bool {
+	for _, i := range g.MissingPackages {
+		if strings.EqualFold(i.PackageName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageNotFoundError is recorded when a required package is not found.
+type PackageNotFoundError struct {
+	PackageName  string
+	SearchedDirs []string
+}
+
+func (e *PackageNotFoundError) Error() string {
+	return fmt.Sprintf("package %s not found in %s", e.PackageName, strings.Join(e.SearchedDirs, ", "))
+}
@@ -0,0 +1,154 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceType(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	RunTypeSection(t,
+		"reference to procedure and function",
+		[]rune(`
+type
+  TProc = reference to procedure;
+  TFunc = reference to function(const Arg: Integer): Integer;
+  TNotify = procedure(Reference: Integer) of object;
+`),
+		ast.TypeSection{
+			&ast.TypeDecl{
+				Ident: asttest.NewIdent("TProc"),
+				Type:  &ast.ProcedureType{FunctionType: ast.FtProcedure, Reference: true},
+			},
+			&ast.TypeDecl{
+				Ident: asttest.NewIdent("TFunc"),
+				Type: &ast.ProcedureType{
+					FunctionType: ast.FtFunction,
+					FormalParameters: ast.FormalParameters{{
+						Opt: &ast.FpoConst,
+						Parameter: &ast.Parameter{
+							IdentList: asttest.NewIdentList("Arg"),
+							Type:      &ast.ParameterType{Type: asttest.NewOrdIdent("Integer")},
+						},
+					}},
+					ReturnType: asttest.NewOrdIdent("Integer"),
+					Reference:  true,
+				},
+			},
+			&ast.TypeDecl{
+				Ident: asttest.NewIdent("TNotify"),
+				Type: &ast.ProcedureType{
+					FunctionType: ast.FtProcedure,
+					FormalParameters: ast.FormalParameters{{
+						Parameter: &ast.Parameter{
+							IdentList: asttest.NewIdentList("Reference"),
+							Type:      &ast.ParameterType{Type: asttest.NewOrdIdent("Integer")},
+						},
+					}},
+					OfObject: true,
+				},
+			},
+		},
+	)
+}
+
+func TestAnonymousMethod(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TProc = reference to procedure;
+  TFunc = reference to function(const Arg: Integer): Integer;
+  TWorker = class
+    FCount: Integer;
+    procedure Run(Base: Integer);
+  end;
+
+procedure Queue(const AProc: TProc);
+
+var
+  GlobalCount: Integer;
+
+implementation
+
+procedure Queue(const AProc: TProc);
+begin
+  AProc;
+end;
+
+procedure TWorker.Run(Base: Integer);
+var
+  Local: Integer;
+  Twice: TFunc;
+begin
+  Local := 1;
+  Queue(procedure
+    var
+      Inner: Integer;
+    begin
+      Inner := Local + GlobalCount;
+      Self.FCount := Inner;
+    end);
+  Twice := function(const Arg: Integer): Integer
+    begin
+      Result := Arg * 2 + Base;
+    end;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	run := unit.ImplementationSection.DeclSections[1].(*ast.FunctionDecl)
+	declBase := run.FormalParameters[0]
+	declLocal := run.Block.DeclSections[0].(ast.VarSection)[0]
+	stmts := run.Block.Body.(*ast.CompoundStmt).StmtList
+
+	t.Run("procedure as an argument", func(t *testing.T) {
+		call := stmts[1].Body.(*ast.CallStatement)
+		if !assert.Len(t, call.ExprList, 1) {
+			return
+		}
+		proc, ok := call.ExprList[0].SimpleExpression.Term.Factor.(*ast.AnonymousMethod)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, ast.FtProcedure, proc.FunctionType)
+		assert.Len(t, proc.Block.DeclSections, 1)
+		assert.Len(t, proc.Block.Body.(*ast.CompoundStmt).StmtList, 2)
+
+		if assert.Len(t, proc.Captures, 2) {
+			assert.Same(t, declLocal, proc.Captures[0].Node)
+			assert.IsType(t, &ast.Self{}, proc.Captures[1].Node)
+		}
+	})
+
+	t.Run("function assigned to a variable", func(t *testing.T) {
+		assign := stmts[2].Body.(*ast.AssignStatement)
+		fn, ok := assign.Expression.SimpleExpression.Term.Factor.(*ast.AnonymousMethod)
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, ast.FtFunction, fn.FunctionType)
+		assert.Len(t, fn.FormalParameters, 1)
+		asttest.ClearLocations(t, fn.ReturnType)
+		assert.Equal(t, asttest.NewOrdIdent("Integer"), fn.ReturnType)
+
+		assert.Equal(t, astcore.Decls{declBase.ToDeclarations()[0]}, fn.Captures)
+	})
+}
@@ -0,0 +1,140 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributes(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  JSONNameAttribute = class(TCustomAttribute)
+  public
+    constructor Create(const AName: string);
+  end;
+  TestCaseAttribute = class(TCustomAttribute)
+  public
+    constructor Create(const AName, AValues: string);
+  end;
+  Ignore = class(TCustomAttribute)
+  end;
+
+  [JSONName('person')]
+  TPerson = class
+  private
+    [JSONName('id')]
+    FId: Integer;
+    [JSONName('name'), Ignore]
+    FName: string;
+  public
+    [TestCase('A', '1,2')]
+    [TestCase('B', '3,4')]
+    procedure Check(const AValues: string; [Ignore] AName: string);
+    [JSONName('ID')]
+    property Id: Integer read FId;
+  end;
+
+  TPoint = record
+    [JSONName('x')]
+    X: Integer;
+    [Ignore] Y: Integer;
+  end;
+
+implementation
+
+procedure TPerson.Check(const AValues: string; AName: string);
+begin
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+	jsonNameDecl := typeSection[0]
+	testCaseDecl := typeSection[1]
+	ignoreDecl := typeSection[2]
+	personDecl := typeSection[3]
+	personType := personDecl.Type.(*ast.CustomClassType)
+
+	assertAttribute := func(t *testing.T, attr *ast.Attribute, name string, decl *ast.TypeDecl, argCount int) {
+		assert.Equal(t, name, attr.Ident.Name)
+		if assert.NotNil(t, attr.Ref) {
+			assert.Same(t, decl, attr.Ref.Node)
+		}
+		assert.Len(t, attr.ExprList, argCount)
+	}
+
+	t.Run("type declaration", func(t *testing.T) {
+		if assert.Len(t, personDecl.Attributes, 1) {
+			assertAttribute(t, personDecl.Attributes[0], "JSONName", jsonNameDecl, 1)
+		}
+		assert.Nil(t, jsonNameDecl.Attributes)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		fields := personType.Members[0].ClassFieldList
+		if !assert.Len(t, fields, 2) {
+			return
+		}
+		if assert.Len(t, fields[0].Attributes, 1) {
+			assertAttribute(t, fields[0].Attributes[0], "JSONName", jsonNameDecl, 1)
+		}
+		if assert.Len(t, fields[1].Attributes, 2) {
+			assertAttribute(t, fields[1].Attributes[0], "JSONName", jsonNameDecl, 1)
+			assertAttribute(t, fields[1].Attributes[1], "Ignore", ignoreDecl, 0)
+		}
+	})
+
+	t.Run("methods and parameters", func(t *testing.T) {
+		methods := personType.Members[1].ClassMethodList
+		if !assert.Len(t, methods, 1) {
+			return
+		}
+		if assert.Len(t, methods[0].Attributes, 2) {
+			assertAttribute(t, methods[0].Attributes[0], "TestCase", testCaseDecl, 2)
+			assertAttribute(t, methods[0].Attributes[1], "TestCase", testCaseDecl, 2)
+		}
+		params := methods[0].Heading.(*ast.FunctionHeading).FormalParameters
+		if assert.Len(t, params, 2) {
+			assert.Nil(t, params[0].Attributes)
+			if assert.Len(t, params[1].Attributes, 1) {
+				assertAttribute(t, params[1].Attributes[0], "Ignore", ignoreDecl, 0)
+			}
+		}
+	})
+
+	t.Run("properties", func(t *testing.T) {
+		props := personType.Members[1].ClassPropertyList
+		if assert.Len(t, props, 1) && assert.Len(t, props[0].Attributes, 1) {
+			assertAttribute(t, props[0].Attributes[0], "JSONName", jsonNameDecl, 1)
+		}
+	})
+
+	t.Run("record fields", func(t *testing.T) {
+		fieldDecls := typeSection[4].Type.(*ast.RecType).FieldList.FieldDecls
+		if !assert.Len(t, fieldDecls, 2) {
+			return
+		}
+		if assert.Len(t, fieldDecls[0].Attributes, 1) {
+			assertAttribute(t, fieldDecls[0].Attributes[0], "JSONName", jsonNameDecl, 1)
+		}
+		if assert.Len(t, fieldDecls[1].Attributes, 1) {
+			assertAttribute(t, fieldDecls[1].Attributes[0], "Ignore", ignoreDecl, 0)
+		}
+	})
+}
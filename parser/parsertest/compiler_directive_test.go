@@ -0,0 +1,162 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompilerDirectives(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+{$H+}
+interface
+
+{$WARN SYMBOL_DEPRECATED OFF}
+{$IFDEF NEVER}
+{$R Never.res}
+{$ENDIF}
+
+implementation
+
+{$R *.dfm}
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, ast.CompilerDirectives{
+		{Name: "H", Argument: "+", Args: []string{"+"}, Location: asttest.NewIdentLocation(2, 1, 9, 6)},
+		{
+			Name:     "WARN",
+			Argument: "SYMBOL_DEPRECATED OFF",
+			Args:     []string{"SYMBOL_DEPRECATED", "OFF"},
+			Location: asttest.NewIdentLocation(5, 1, 26, 30),
+		},
+		{Name: "IFDEF", Argument: "NEVER", Args: []string{"NEVER"}, Location: asttest.NewIdentLocation(6, 1, 56, 15)},
+		{Name: "ENDIF", Location: asttest.NewIdentLocation(8, 1, 86, 9)},
+		{Name: "R", Argument: "*.dfm", Args: []string{"*.dfm"}, Location: asttest.NewIdentLocation(12, 1, 112, 11)},
+	}, unit.CompilerDirectives)
+
+	resources := unit.CompilerDirectives.ByName("r")
+	if assert.Len(t, resources, 1) {
+		assert.Equal(t, "*.dfm", resources[0].Argument)
+	}
+}
+
+func TestConditionalDirectiveWhichCannotBeEvaluated(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+const
+{$IF SizeOf(Pointer) = 8}
+  PointerSize = 8;
+{$ELSE}
+  PointerSize = 4;
+{$IFEND}
+
+implementation
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	_, err := parser.ParseUnit()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "undeclared identifier SizeOf")
+		assert.Contains(t, err.Error(), "at 6:1")
+	}
+}
+
+func TestCompilerDirectivesAttachedToNodes(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+{$WARN SYMBOL_DEPRECATED OFF}
+const
+  A = 1;
+  {$J+}
+  B: Integer = 2;
+
+type
+  {$M+}
+  TNumber = Integer;
+
+var
+  X: Integer;
+
+implementation
+
+{$R-}
+procedure Run;
+var
+  {$IFDEF NEVER} Y: Integer; {$ENDIF}
+  Z: Integer;
+begin
+  X := 1;
+  {$R+}
+  Z := X;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	names := func(directives ast.CompilerDirectives) []string {
+		var r []string
+		for _, d := range directives {
+			r = append(r, d.Name+d.Argument)
+			shared := false
+			for _, u := range unit.CompilerDirectives {
+				shared = shared || u == d
+			}
+			assert.True(t, shared, "%s is not the one of the unit", d.Name)
+		}
+		return r
+	}
+
+	intfDecls := unit.InterfaceSection.InterfaceDecls
+	if !assert.Len(t, intfDecls, 3) {
+		return
+	}
+	constSection := intfDecls[0].(ast.ConstSection)
+	assert.Equal(t, []string{"WARNSYMBOL_DEPRECATED OFF"}, names(constSection[0].CompilerDirectives))
+	assert.Equal(t, []string{"J+"}, names(constSection[1].CompilerDirectives))
+	assert.Equal(t, []string{"M+"}, names(intfDecls[1].(ast.TypeSection)[0].CompilerDirectives))
+	assert.Nil(t, intfDecls[2].(ast.VarSection)[0].CompilerDirectives)
+
+	run := unit.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+	assert.Equal(t, []string{"R-"}, names(run.CompilerDirectives))
+	varSection := run.Block.DeclSections[0].(ast.VarSection)
+	if assert.Len(t, varSection, 1) {
+		assert.Equal(t, []string{"IFDEFNEVER", "ENDIF"}, names(varSection[0].CompilerDirectives))
+	}
+	stmts := run.Block.Body.(*ast.CompoundStmt).StmtList
+	if assert.Len(t, stmts, 2) {
+		assert.Nil(t, stmts[0].CompilerDirectives)
+		assert.Equal(t, []string{"R+"}, names(stmts[1].CompilerDirectives))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -263,3 +264,162 @@ func TestConstSectionl(t *testing.T) {
 		}(),
 	)
 }
+
+func TestTypedConstant(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	parse := func(t *testing.T, src string) (*ast.Unit, error) {
+		text := []rune("unit U1;\ninterface\n" + src + "\nimplementation\nend.\n")
+		parser := NewTestUnitParser(&text)
+		parser.NextToken()
+		return parser.ParseUnit()
+	}
+	constDecls := func(unit *ast.Unit) ast.ConstSection {
+		for _, decl := range unit.InterfaceSection.InterfaceDecls {
+			if sect, ok := decl.(ast.ConstSection); ok {
+				return sect
+			}
+		}
+		return nil
+	}
+
+	t.Run("array constant indexed by enumerated type", func(t *testing.T) {
+		unit, err := parse(t, `type
+  TColor = (Red, Green);
+const
+  Names: array[TColor] of string = ('Red', 'Green');`)
+		if !assert.NoError(t, err) {
+			return
+		}
+		decl := constDecls(unit)[0]
+		assert.Nil(t, decl.ConstExpr)
+		asttest.ClearLocations(t, decl.TypedConstant)
+		assert.Equal(t, ast.ArrayConstant{
+			asttest.NewConstExpr(asttest.NewString("'Red'")),
+			asttest.NewConstExpr(asttest.NewString("'Green'")),
+		}, decl.TypedConstant)
+	})
+
+	t.Run("record constant", func(t *testing.T) {
+		unit, err := parse(t, `type
+  TPoint = record
+    X, Y: Integer;
+  end;
+const
+  Default: TPoint = (X: 0; Y: -1);`)
+		if !assert.NoError(t, err) {
+			return
+		}
+		recType := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0].Type.(*ast.RecType)
+		rec, ok := constDecls(unit)[0].TypedConstant.(ast.RecordConstant)
+		if assert.True(t, ok) && assert.Len(t, rec, 2) {
+			assert.Equal(t, "X", rec[0].Name)
+			assert.Same(t, recType.FieldList.FieldDecls[0], rec[0].Ref.Node)
+			assert.Equal(t, "Y", rec[1].Name)
+			assert.Same(t, recType.FieldList.FieldDecls[0], rec[1].Ref.Node)
+			asttest.ClearLocations(t, rec[0].Value)
+			assert.Equal(t, asttest.NewConstExpr(asttest.NewNumber("0")), rec[0].Value)
+		}
+	})
+
+	t.Run("nested arrays and records", func(t *testing.T) {
+		unit, err := parse(t, `type
+  TPoint = record
+    X, Y: Integer;
+  end;
+  TLine = record
+    Points: array[0..1] of TPoint;
+  end;
+const
+  Matrix: array[1..2, 0..2] of Integer = ((1, 2, 3), (4, 5, 6));
+  Line: TLine = (Points: ((X: 0; Y: 0), (X: 1; Y: 1)));`)
+		if !assert.NoError(t, err) {
+			return
+		}
+		sect := constDecls(unit)
+		matrix := sect[0].TypedConstant.(ast.ArrayConstant)
+		if assert.Len(t, matrix, 2) {
+			assert.Len(t, matrix[1].(ast.ArrayConstant), 3)
+		}
+		line := sect[1].TypedConstant.(ast.RecordConstant)
+		if assert.Len(t, line, 1) {
+			points := line[0].Value.(ast.ArrayConstant)
+			if assert.Len(t, points, 2) {
+				assert.Len(t, points[1].(ast.RecordConstant), 2)
+			}
+		}
+	})
+
+	t.Run("expression in parentheses", func(t *testing.T) {
+		unit, err := parse(t, `const
+  Half: Integer = (10 + 2) div 2;`)
+		if assert.NoError(t, err) {
+			decl := constDecls(unit)[0]
+			assert.NotNil(t, decl.ConstExpr)
+			assert.Nil(t, decl.TypedConstant)
+		}
+	})
+
+	t.Run("unknown types", func(t *testing.T) {
+		unit, err := parse(t, `const
+  Items: TUnknownArray = (1, 2, 3);
+  Origin: TUnknownPoint = (X: 0; Y: 0);`)
+		if assert.NoError(t, err) {
+			sect := constDecls(unit)
+			assert.Len(t, sect[0].TypedConstant.(ast.ArrayConstant), 3)
+			origin := sect[1].TypedConstant.(ast.RecordConstant)
+			if assert.Len(t, origin, 2) {
+				assert.Nil(t, origin[0].Ref)
+			}
+		}
+	})
+
+	t.Run("element count mismatch", func(t *testing.T) {
+		_, err := parse(t, `type
+  TColor = (Red, Green, Blue);
+const
+  Names: array[TColor] of string = ('Red', 'Green');`)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "array constant expects 3 elements but was 2")
+		}
+
+		_, err = parse(t, `const
+  Max = 3;
+  Values: array[1..Max] of Integer = (1, 2, 3, 4);`)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "array constant expects 3 elements but was 4")
+		}
+
+		_, err = parse(t, `const
+  Flags: array[$00..$01] of Integer = (1, 2, 3);`)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "array constant expects 2 elements but was 3")
+		}
+
+		_, err = parse(t, `const
+  Letters: array['a'..'c'] of Integer = (1, 2);`)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "array constant expects 3 elements but was 2")
+		}
+
+		_, err = parse(t, `const
+  Flags: array[Boolean] of Char = ('F', 'T');
+  Digits: array['0'..'2'] of Byte = (0, 1, 2);
+  Bits: array[%0..%1] of Byte = (0, 1);
+  Controls: array[#0..#2] of Byte = (0, 1, 2);
+  Marks: array[Char] of Boolean = (False, True);`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := parse(t, `type
+  TPoint = record
+    X, Y: Integer;
+  end;
+const
+  P: TPoint = (X: 0; Z: 0);`)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "unknown field Z")
+		}
+	})
+}
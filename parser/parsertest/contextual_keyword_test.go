@@ -0,0 +1,308 @@
+package parsertest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+// contextualKeywords are the words which are keywords only in some contexts.
+// They are directives, property specifiers, visibility specifiers and so on.
+var contextualKeywords = strings.Fields(`
+	ABSOLUTE ABSTRACT ASSEMBLER AT AUTOMATED CDECL CONTAINS DEFAULT DEPRECATED
+	DISPID DYNAMIC EXPERIMENTAL EXPORT EXTERNAL FAR FINAL FORWARD HELPER IMPLEMENTS
+	INDEX LOCAL MESSAGE NAME NEAR NODEFAULT ON OPERATOR OVERLOAD OVERRIDE PACKAGE
+	PASCAL PLATFORM PRIVATE PROTECTED PUBLIC PUBLISHED READ READONLY REFERENCE
+	REGISTER REINTRODUCE REQUIRES RESIDENT SAFECALL SEALED STATIC STDCALL STORED
+	STRICT VARARGS VIRTUAL WRITE WRITEONLY
+`)
+
+func TestContextualKeywordsAsIdentifiers(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	for _, kw := range contextualKeywords {
+		w := strings.Title(strings.ToLower(kw))
+		t.Run(w, func(t *testing.T) {
+			text := []rune(`unit U1;
+interface
+type
+  TRec = record
+    ` + w + `: Integer;
+  end;
+  TObj = class
+    ` + w + `: Integer;
+    ` + w + `2, ` + w + `3: Integer;
+  private
+    F` + w + `: Integer;
+    function Get` + w + `(` + w + `: Integer): string;
+  public
+    procedure ` + w + `M(var ` + w + `: Integer); message 1;
+    procedure ` + w + `V; virtual; abstract;
+    property ` + w + `P: Integer read F` + w + ` write F` + w + ` default 0;
+    property Items[` + w + `: Integer]: string read Get` + w + `; default;
+  end;
+  TObj2 = class sealed(TObj)
+  strict private
+    ` + w + `4: Integer;
+  end;
+  TEnum = (` + w + `E, ` + w + `F);
+procedure ` + w + `X(const ` + w + `: string); external 'lib.dll' name '` + w + `';
+var
+  ` + w + `: Integer;
+  Obj: TObj;
+implementation
+function TObj.Get` + w + `(` + w + `: Integer): string;
+begin
+  Result := '';
+end;
+procedure TObj.` + w + `M(var ` + w + `: Integer);
+var
+  ` + w + `5: Integer;
+begin
+  ` + w + `5 := ` + w + `;
+  Self.` + w + ` := ` + w + `5;
+end;
+procedure Run(` + w + `: Integer);
+var
+  X: Integer;
+begin
+  X := ` + w + `;
+  try
+    ` + w + ` := X;
+  except
+    ` + w + ` := X + 1;
+  end;
+  with Obj do
+    ` + w + `2 := ` + w + `;
+end;
+end.
+`)
+			parser := NewTestUnitParser(&text)
+			parser.NextToken()
+			unit, err := parser.ParseUnit()
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+			recType := typeSection[0].Type.(*ast.RecType)
+			assert.Equal(t, w, recType.FieldList.FieldDecls[0].IdentList[0].Name)
+
+			classType := typeSection[1].Type.(*ast.CustomClassType)
+			if assert.Len(t, classType.Members, 3) {
+				assert.Equal(t, ast.CvDefault, classType.Members[0].Visibility)
+				assert.Len(t, classType.Members[0].ClassFieldList, 2)
+				assert.Equal(t, w, classType.Members[0].ClassFieldList[0].IdentList[0].Name)
+				assert.Equal(t, ast.CvPrivate, classType.Members[1].Visibility)
+				assert.Equal(t, ast.CvPublic, classType.Members[2].Visibility)
+				assert.Len(t, classType.Members[2].ClassMethodList, 2)
+				assert.Len(t, classType.Members[2].ClassPropertyList, 2)
+			}
+
+			childType := typeSection[2].Type.(*ast.CustomClassType)
+			assert.True(t, childType.Sealed)
+
+			varSection := unit.InterfaceSection.InterfaceDecls[2].(ast.VarSection)
+			assert.Equal(t, w, varSection[0].IdentList[0].Name)
+		})
+	}
+}
+
+func TestContextualKeywordsAtHeadOfTypes(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+interface
+type
+  TRec = record
+    Helper: Integer;
+    Public, Private: Boolean;
+  end;
+  TObj = class
+    Sealed: Boolean;
+  end;
+  TObj2 = class
+    Abstract, Strict: Boolean;
+  end;
+implementation
+procedure Run;
+var
+  On: Boolean;
+begin
+  try
+    On := True;
+  except
+    On := False;
+  end;
+end;
+end.
+`)
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+	recType, ok := typeSection[0].Type.(*ast.RecType)
+	if assert.True(t, ok) {
+		assert.Len(t, recType.FieldList.FieldDecls, 2)
+	}
+	classType := typeSection[1].Type.(*ast.CustomClassType)
+	assert.False(t, classType.Sealed)
+	assert.Equal(t, "Sealed", classType.Members[0].ClassFieldList[0].IdentList[0].Name)
+	classType2 := typeSection[2].Type.(*ast.CustomClassType)
+	assert.False(t, classType2.Abstract)
+	assert.Len(t, classType2.Members, 1)
+
+	run := unit.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+	tryStmt := run.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.TryExceptStmt)
+	assert.Nil(t, tryStmt.ExceptionBlock.Handlers)
+	assert.Len(t, tryStmt.ExceptionBlock.Else, 1)
+}
+
+func TestContextualKeywordsAsDirectives(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+interface
+type
+  IFoo = interface
+  end;
+  TObj = class(TInterfacedObject, IFoo)
+  private
+    FName: string;
+    FFoo: IFoo;
+    function GetItem(Index: Integer): string;
+  public
+    procedure WMPaint(var Msg: Integer); message 15;
+    procedure Run; dynamic; stdcall;
+    class procedure Make; static;
+    property Name: string read FName write FName;
+    property Items[Index: Integer]: string read GetItem; default;
+  end;
+implementation
+end.
+`)
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+	classType := typeSection[1].Type.(*ast.CustomClassType)
+	public := classType.Members[1]
+
+	methods := public.ClassMethodList
+	if assert.Len(t, methods, 3) {
+		assert.Equal(t, ast.ClassMethodDirectiveList{ast.CmdMessage}, methods[0].Directives)
+		if assert.NotNil(t, methods[0].Message) {
+			assert.Equal(t, "15", methods[0].Message.SimpleExpression.Term.Factor.(*ast.NumberFactor).Value)
+		}
+		assert.Equal(t, ast.ClassMethodDirectiveList{ast.CmdDynamic, ast.CmdStdcall}, methods[1].Directives)
+		assert.Nil(t, methods[1].Message)
+		assert.Equal(t, ast.ClassMethodDirectiveList{ast.CmdStatic}, methods[2].Directives)
+	}
+
+	props := public.ClassPropertyList
+	if assert.Len(t, props, 2) {
+		assert.False(t, props[0].DefaultProperty)
+		assert.True(t, props[1].DefaultProperty)
+	}
+}
+
+func TestContextualKeywordsAfterHeadings(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+interface
+const
+  Message = 1;
+  Name = 'U1';
+  Default: Integer = 2;
+type
+  TObj = class
+  const
+    Register = 3;
+    Index = 4;
+  public
+    procedure Run;
+  end;
+procedure Foo;
+var Name2: Integer;
+procedure Bar; stdcall;
+const Index = 5;
+implementation
+procedure Foo;
+var Name: Integer;
+begin
+  Name := Message;
+end;
+procedure Bar;
+var
+  Index: Integer;
+begin
+  Index := Default;
+end;
+procedure TObj.Run;
+begin
+end;
+end.
+`)
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	intfDecls := unit.InterfaceSection.InterfaceDecls
+	if !assert.Len(t, intfDecls, 6) {
+		return
+	}
+	constSection := intfDecls[0].(ast.ConstSection)
+	if assert.Len(t, constSection, 3) {
+		assert.Equal(t, "Message", constSection[0].Ident.Name)
+		assert.Equal(t, "Name", constSection[1].Ident.Name)
+		assert.Equal(t, "Default", constSection[2].Ident.Name)
+	}
+	classType := intfDecls[1].(ast.TypeSection)[0].Type.(*ast.CustomClassType)
+	if assert.Len(t, classType.Members, 2) {
+		nested := classType.Members[0].NestedDeclSections
+		if assert.Len(t, nested, 1) {
+			classConsts := nested[0].(ast.ConstSection)
+			if assert.Len(t, classConsts, 2) {
+				assert.Equal(t, "Register", classConsts[0].Ident.Name)
+				assert.Equal(t, "Index", classConsts[1].Ident.Name)
+			}
+		}
+	}
+	foo := intfDecls[2].(*ast.ExportedHeading)
+	assert.Empty(t, foo.Directives)
+	assert.Equal(t, "Name2", intfDecls[3].(ast.VarSection)[0].IdentList[0].Name)
+	bar := intfDecls[4].(*ast.ExportedHeading)
+	assert.Equal(t, []ast.Directive{ast.DrStdcall}, bar.Directives)
+	assert.Equal(t, "Index", intfDecls[5].(ast.ConstSection)[0].Ident.Name)
+
+	implDecls := unit.ImplementationSection.DeclSections
+	if !assert.Len(t, implDecls, 3) {
+		return
+	}
+	fooImpl := implDecls[0].(*ast.FunctionDecl)
+	assert.Empty(t, fooImpl.Directives)
+	assert.Equal(t, "Name", fooImpl.Block.DeclSections[0].(ast.VarSection)[0].IdentList[0].Name)
+	barImpl := implDecls[1].(*ast.FunctionDecl)
+	assert.Empty(t, barImpl.Directives)
+	assert.Equal(t, "Index", barImpl.Block.DeclSections[0].(ast.VarSection)[0].IdentList[0].Name)
+	stmt := barImpl.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+	if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+		assert.Equal(t, "Index", stmt.Designator.QualId.Ident.Name)
+		assert.Equal(t, 27, stmt.Designator.QualId.Ident.Ref.Location.Start.Line)
+	}
+}
@@ -38,7 +38,7 @@ func TestExpression(t *testing.T) {
 		&ast.Expression{
 			SimpleExpression: &ast.SimpleExpression{
 				Term: &ast.Term{
-					Factor: &ast.NumberFactor{Value: "7"},
+					Factor: &ast.NumberFactor{Value: "7", Radix: 10, Int: 7, Float: 7},
 				},
 			},
 		},
@@ -48,6 +48,21 @@ func TestExpression(t *testing.T) {
 		[]rune(`7`),
 		asttest.NewExpression(asttest.NewNumber("7")),
 	)
+	run(
+		"hexadecimal integer", false,
+		[]rune(`$FF_00`),
+		asttest.NewExpression(&ast.NumberFactor{Value: "$FF_00", Radix: 16, Int: 0xFF00, Float: 0xFF00}),
+	)
+	run(
+		"binary integer", false,
+		[]rune(`%1010`),
+		asttest.NewExpression(&ast.NumberFactor{Value: "%1010", Radix: 2, Int: 10, Float: 10}),
+	)
+	run(
+		"real with exponent", false,
+		[]rune(`1.5E-3`),
+		asttest.NewExpression(&ast.NumberFactor{Value: "1.5E-3", Radix: 10, Float: 0.0015}),
+	)
 
 	run(
 		"true constant of string", false,
@@ -55,7 +70,30 @@ func TestExpression(t *testing.T) {
 		&ast.Expression{
 			SimpleExpression: &ast.SimpleExpression{
 				Term: &ast.Term{
-					Factor: &ast.StringFactor{Value: "'abc'"},
+					Factor: &ast.StringFactor{Value: "'abc'", Decoded: "abc"},
+				},
+			},
+		},
+	)
+	run(
+		"character string with quotes and control strings", false,
+		[]rune(`'It''s'#13#$0A'C:\'`),
+		asttest.NewExpression(&ast.StringFactor{Value: `'It''s'#13#$0A'C:\'`, Decoded: "It's\r\nC:\\"}),
+	)
+	run(
+		"standalone control string", false,
+		[]rune(`^M`),
+		asttest.NewExpression(&ast.StringFactor{Value: `^M`, Decoded: "\r"}),
+	)
+	run(
+		"control string after equal", false,
+		[]rune(`C = ^M`),
+		&ast.Expression{
+			SimpleExpression: asttest.NewSimpleExpression(asttest.NewIdent("C", asttest.NewIdentLocation(1, 1, 0, 1, 2, 1))),
+			RelOpSimpleExpressions: []*ast.RelOpSimpleExpression{
+				{
+					RelOp:            "=",
+					SimpleExpression: asttest.NewSimpleExpression(&ast.StringFactor{Value: `^M`, Decoded: "\r"}),
 				},
 			},
 		},
@@ -76,7 +114,7 @@ func TestExpression(t *testing.T) {
 	run(
 		"integer constant", false,
 		[]rune(`15`),
-		asttest.NewExpression(&ast.NumberFactor{Value: "15"}),
+		asttest.NewExpression(&ast.NumberFactor{Value: "15", Radix: 10, Int: 15, Float: 15}),
 	)
 
 	run(
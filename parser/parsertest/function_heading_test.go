@@ -206,6 +206,20 @@ func TestExportHeading(t *testing.T) {
 		},
 	)
 
+	index := 12
+	run(
+		"function GetTickCount: Integer; stdcall; external 'kernel32.dll' index 12;",
+		&ast.ExportedHeading{
+			FunctionHeading: &ast.FunctionHeading{
+				Type:       ast.FtFunction,
+				Ident:      asttest.NewIdent("GetTickCount"),
+				ReturnType: asttest.NewOrdIdent("Integer"),
+			},
+			Directives:      []ast.Directive{ast.DrStdcall, ast.DrExternal},
+			ExternalOptions: &ast.ExternalOptions{LibraryName: "'kernel32.dll'", Index: &index},
+		},
+	)
+
 	run(
 		"function Divide(X, Y: Real): Real; overload;",
 		&ast.ExportedHeading{
@@ -0,0 +1,110 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineVariables(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+implementation
+
+var
+  X: Integer;
+
+procedure Run(const Items: array of Integer);
+begin
+  var Sum: Integer := 0;
+  var A, B: Integer;
+  begin
+    var X := 1;
+    Sum := X;
+  end;
+  Sum := X;
+  for var I := 0 to 9 do
+    Sum := Sum + I;
+  for var Item: Integer in Items do
+    Sum := Sum + Item;
+  for X in Items do
+    Sum := Sum + X;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	globalX := unit.ImplementationSection.DeclSections[0].(ast.VarSection)[0]
+	run := unit.ImplementationSection.DeclSections[1].(*ast.FunctionDecl)
+	stmts := run.Block.Body.(*ast.CompoundStmt).StmtList
+	if !assert.Len(t, stmts, 7) {
+		return
+	}
+
+	sum := stmts[0].Body.(*ast.InlineVarStatement)
+	ab := stmts[1].Body.(*ast.InlineVarStatement)
+	innerStmts := stmts[2].Body.(*ast.CompoundStmt).StmtList
+	innerX := innerStmts[0].Body.(*ast.InlineVarStatement)
+
+	assignedFrom := func(stmt *ast.Statement) *ast.DesignatorFactor {
+		return stmt.Body.(*ast.AssignStatement).Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+	}
+
+	t.Run("inline var statements", func(t *testing.T) {
+		assert.Equal(t, "Sum", sum.IdentList[0].Name)
+		assert.NotNil(t, sum.Type)
+		assert.NotNil(t, sum.Expression)
+		assert.False(t, sum.Inferred)
+
+		assert.Len(t, ab.IdentList, 2)
+		assert.NotNil(t, ab.Type)
+		assert.Nil(t, ab.Expression)
+		assert.False(t, ab.Inferred)
+
+		assert.Nil(t, innerX.Type)
+		assert.NotNil(t, innerX.Expression)
+		assert.True(t, innerX.Inferred)
+	})
+
+	t.Run("scope of inline vars", func(t *testing.T) {
+		assign := innerStmts[1].Body.(*ast.AssignStatement)
+		assert.Same(t, sum, assign.Designator.QualId.Ident.Ref.Node)
+		assert.Same(t, innerX, assignedFrom(innerStmts[1]).Designator.QualId.Ident.Ref.Node)
+
+		assert.Same(t, globalX, assignedFrom(stmts[3]).Designator.QualId.Ident.Ref.Node)
+	})
+
+	t.Run("for var", func(t *testing.T) {
+		forStmt := stmts[4].Body.(*ast.ForStmt)
+		if assert.NotNil(t, forStmt.Var) {
+			assert.Equal(t, "I", forStmt.Var.IdentList[0].Name)
+			assert.True(t, forStmt.Var.Inferred)
+			assert.Same(t, forStmt.Var, forStmt.QualId.Ident.Ref.Node)
+		}
+
+		forInStmt := stmts[5].Body.(*ast.ForInStmt)
+		if assert.NotNil(t, forInStmt.Var) {
+			assert.Equal(t, "Item", forInStmt.Var.IdentList[0].Name)
+			assert.False(t, forInStmt.Var.Inferred)
+			assert.NotNil(t, forInStmt.Var.Type)
+		}
+		assert.IsType(t, &ast.FormalParm{}, forInStmt.Enumerable.SimpleExpression.Term.Factor.(*ast.DesignatorFactor).Designator.QualId.Ident.Ref.Node)
+
+		forInGlobal := stmts[6].Body.(*ast.ForInStmt)
+		assert.Nil(t, forInGlobal.Var)
+		assert.Same(t, globalX, forInGlobal.QualId.Ident.Ref.Node)
+	})
+}
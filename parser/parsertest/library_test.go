@@ -0,0 +1,71 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibrary(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`library MyLib;
+
+function Add(A, B: Integer): Integer; stdcall;
+begin
+  Result := A + B;
+end;
+
+exports
+  Add;
+
+procedure Hello; stdcall;
+begin
+end;
+
+procedure Bye; stdcall;
+begin
+end;
+
+exports
+  Hello name 'SayHello' index 2,
+  Bye index 3 name 'SayBye' resident;
+
+begin
+end.
+`)
+
+	p := parser.NewLibraryParser(NewTestProgramContext())
+	p.SetText(&text)
+	p.NextToken()
+	actual, err := p.ParseLibrary()
+	if !assert.NoError(t, err) {
+		return
+	}
+	asttest.ClearLocations(t, actual)
+
+	assert.Equal(t, "MyLib", actual.Ident.Name)
+	assert.Len(t, actual.ProgramBlock.DeclSections, 3)
+	assert.Len(t, actual.ProgramBlock.ExportsStmts1, 2)
+	assert.Equal(t,
+		[]*ast.ExportsItem{
+			{Ident: asttest.NewIdent("Add")},
+			{
+				Ident: asttest.NewIdent("Hello"),
+				Name:  asttest.NewConstExpr(asttest.NewString("'SayHello'")),
+				Index: asttest.NewConstExpr(asttest.NewNumber("2")),
+			},
+			{
+				Ident:    asttest.NewIdent("Bye"),
+				Name:     asttest.NewConstExpr(asttest.NewString("'SayBye'")),
+				Index:    asttest.NewConstExpr(asttest.NewNumber("3")),
+				Resident: true,
+			},
+		},
+		actual.ExportsItems(),
+	)
+}
@@ -0,0 +1,65 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/log/testlog"
+	"github.com/akm/tparser/parser"
+	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLosslessUnit(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1; // lossless
+
+interface
+
+{$IFDEF NEVER}
+uses Missing;
+{$ENDIF}
+
+const
+  { the answer }
+  Answer = 42;
+
+implementation
+
+end.
+`)
+
+	parse := func(lossless bool) *parser.UnitParser {
+		ctx := parser.NewUnitContext(parser.NewProgramContext(parser.Lossless(lossless)))
+		assert.Equal(t, lossless, ctx.IsLossless())
+		p := parser.NewUnitParser(ctx)
+		p.SetText(&text)
+		return p
+	}
+
+	expected, err := func() (interface{}, error) {
+		p := parse(false)
+		p.NextToken()
+		return p.ParseUnit()
+	}()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p := parse(true)
+	first := p.NextToken()
+	assert.Equal(t, " ", first.TrailingTrivia.String())
+	actual, err := p.ParseUnit()
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, actual)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		p := parse(true)
+		tokens := []*token.Token{p.NextToken()}
+		for tokens[len(tokens)-1].Type != token.EOF {
+			tokens = append(tokens, p.NextToken())
+		}
+		assert.Equal(t, string(text), token.SourceText(tokens))
+	})
+}
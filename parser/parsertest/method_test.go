@@ -0,0 +1,237 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodImplementation(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TShape = class
+  private
+    FName: string;
+  public
+    constructor Create(AName: string);
+    destructor Destroy; override;
+    function GetName: string;
+    procedure Draw; overload;
+    procedure Draw(X, Y: Integer); overload;
+    class function Count: Integer;
+    property Name: string read FName;
+  end;
+
+implementation
+
+constructor TShape.Create(AName: string);
+begin
+  FName := AName;
+end;
+
+destructor TShape.Destroy;
+begin
+  inherited;
+end;
+
+function TShape.GetName;
+begin
+  Result := Self.FName;
+end;
+
+procedure TShape.Draw;
+begin
+end;
+
+procedure TShape.Draw(X, Y: Integer);
+begin
+  Draw;
+end;
+
+class function TShape.Count: Integer;
+begin
+  Result := 0;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeDecl := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0]
+	classType := typeDecl.Type.(*ast.CustomClassType)
+	publicMethods := classType.Members[1].ClassMethodList
+	fieldFName := classType.Members[0].ClassFieldList[0]
+
+	declSections := unit.ImplementationSection.DeclSections
+	if !assert.Len(t, declSections, 6) {
+		return
+	}
+	methods := make([]*ast.FunctionDecl, len(declSections))
+	for i, sect := range declSections {
+		methods[i] = sect.(*ast.FunctionDecl)
+		if assert.Len(t, methods[i].ClassTypes, 1) {
+			assert.Equal(t, "TShape", methods[i].ClassTypes[0].Name)
+			if assert.NotNil(t, methods[i].ClassTypes[0].Ref) {
+				assert.Same(t, typeDecl, methods[i].ClassTypes[0].Ref.Node)
+			}
+		}
+	}
+
+	t.Run("constructor", func(t *testing.T) {
+		m := methods[0]
+		assert.Equal(t, ast.FtConstructor, m.Type)
+		assert.Equal(t, "Create", m.Ident.Name)
+		assert.Same(t, publicMethods[0], m.Method)
+
+		stmt := m.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+			assert.Same(t, fieldFName, stmt.Designator.QualId.Ident.Ref.Node)
+		}
+		arg := stmt.Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		if assert.NotNil(t, arg.Designator.QualId.Ident.Ref) {
+			assert.Same(t, m.FormalParameters[0], arg.Designator.QualId.Ident.Ref.Node)
+		}
+	})
+
+	t.Run("destructor", func(t *testing.T) {
+		m := methods[1]
+		assert.Equal(t, ast.FtDestructor, m.Type)
+		assert.Same(t, publicMethods[1], m.Method)
+	})
+
+	t.Run("function with omitted return type", func(t *testing.T) {
+		m := methods[2]
+		assert.Equal(t, ast.FtFunction, m.Type)
+		assert.Same(t, publicMethods[2], m.Method)
+		assert.Equal(t, publicMethods[2].Heading.(*ast.FunctionHeading).ReturnType, m.ReturnType)
+
+		stmt := m.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		factor := stmt.Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		if assert.NotNil(t, factor.Designator.QualId.Ident.Ref) {
+			self, ok := factor.Designator.QualId.Ident.Ref.Node.(*ast.Self)
+			if assert.True(t, ok) {
+				assert.Same(t, typeDecl, self.TypeDecl)
+			}
+		}
+	})
+
+	t.Run("overloaded methods", func(t *testing.T) {
+		assert.Same(t, publicMethods[3], methods[3].Method)
+		assert.Same(t, publicMethods[4], methods[4].Method)
+
+		stmt := methods[4].Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.CallStatement)
+		if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+			assert.IsType(t, &ast.ClassMethod{}, stmt.Designator.QualId.Ident.Ref.Node)
+		}
+	})
+
+	t.Run("class method", func(t *testing.T) {
+		m := methods[5]
+		assert.True(t, m.ClassMethod)
+		assert.True(t, publicMethods[5].ClassMethod)
+		assert.Same(t, publicMethods[5], m.Method)
+	})
+}
+
+func TestNestedMethodImplementation(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`procedure TOuter.TInner.Proc(A: Integer);
+begin
+end;`)
+
+	parser := NewTestParser(&text)
+	parser.NextToken()
+	res, err := parser.ParseProcedureDeclSection()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"TOuter", "TInner"}, []string{res.ClassTypes[0].Name, res.ClassTypes[1].Name})
+		assert.Equal(t, "Proc", res.Ident.Name)
+		assert.Len(t, res.FormalParameters, 1)
+		assert.Nil(t, res.Method)
+	}
+}
+
+func TestMethodImplementationWithoutClass(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`constructor Create;
+begin
+end;`)
+
+	parser := NewTestParser(&text)
+	parser.NextToken()
+	_, err := parser.ParseProcedureDeclSection()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "constructor requires class type before Create")
+	}
+}
+
+func TestOverloadedMethodImplementationByParameterTypes(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TFoo = class
+  public
+    procedure Foo(A: Integer); overload;
+    procedure Foo(A: string); overload;
+    procedure Bar(A: Integer); overload;
+    procedure Bar(B: Integer; C: Integer = 0); overload;
+    procedure Baz(A: Integer); overload;
+    procedure Baz(A: Integer); overload;
+  end;
+
+implementation
+
+procedure TFoo.Foo(A: string);
+begin
+end;
+
+procedure TFoo.Foo(A: Integer);
+begin
+end;
+
+procedure TFoo.Bar(B, C: Integer);
+begin
+end;
+
+procedure TFoo.Baz(A: Integer);
+begin
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	classType := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0].Type.(*ast.CustomClassType)
+	declared := classType.Members[0].ClassMethodList
+	impls := unit.ImplementationSection.DeclSections
+
+	assert.Same(t, declared[1], impls[0].(*ast.FunctionDecl).Method)
+	assert.Same(t, declared[0], impls[1].(*ast.FunctionDecl).Method)
+	assert.Same(t, declared[3], impls[2].(*ast.FunctionDecl).Method)
+	// Ambiguous
+	assert.Nil(t, impls[3].(*ast.FunctionDecl).Method)
+}
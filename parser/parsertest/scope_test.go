@@ -0,0 +1,51 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeAfterLookaheadInScope(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	// The members after the nested type are looked ahead for their attributes
+	// in the scope of TFoo.
+	text := []rune(`unit U1;
+
+interface
+
+implementation
+
+type
+  TItem = string;
+  TFoo = class
+  public
+    type
+      TItem = Integer;
+    var
+      Count: Integer;
+  end;
+
+var
+  Item: TItem;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	itemDecl := unit.ImplementationSection.DeclSections[0].(ast.TypeSection)[0]
+	varDecl := unit.ImplementationSection.DeclSections[1].(ast.VarSection)[0]
+	typeId := varDecl.Type.(*ast.TypeId)
+	if assert.NotNil(t, typeId.Ref) {
+		assert.Same(t, itemDecl, typeId.Ref.Node)
+	}
+}
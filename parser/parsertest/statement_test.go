@@ -69,3 +69,29 @@ end.
 		},
 	)
 }
+
+func TestAssignStatementWithControlString(t *testing.T) {
+	RunStatementTest(t,
+		"standalone control string",
+		[]rune(`S := ^M;`),
+		&ast.Statement{
+			Body: &ast.AssignStatement{
+				Designator: asttest.NewDesignator("S"),
+				Expression: asttest.NewExpression(&ast.StringFactor{Value: "^M", Decoded: "\r"}),
+			},
+		},
+	)
+	RunStatementTest(t,
+		"control strings in arguments",
+		[]rune(`Write(^M, ^J);`),
+		&ast.Statement{
+			Body: &ast.CallStatement{
+				Designator: asttest.NewDesignator(asttest.NewIdent("Write")),
+				ExprList: ast.ExprList{
+					asttest.NewExpression(&ast.StringFactor{Value: "^M", Decoded: "\r"}),
+					asttest.NewExpression(&ast.StringFactor{Value: "^J", Decoded: "\n"}),
+				},
+			},
+		},
+	)
+}
@@ -22,6 +22,23 @@ func TestAssemblerStatement(t *testing.T) {
 		})
 	}
 
+	reg := func(name string) *ast.AsmRegister { return &ast.AsmRegister{Ident: asttest.NewIdent(name)} }
+	num := func(v string) *ast.AsmNumber { return &ast.AsmNumber{Value: v} }
+	str := func(v string) *ast.AsmString { return &ast.AsmString{Value: v} }
+	ident := func(name string, args ...interface{}) *ast.AsmIdent {
+		return &ast.AsmIdent{IdentRef: asttest.NewIdentRef(name, args...)}
+	}
+	instr := func(opcode string, operands ...ast.AsmOperand) *ast.AsmInstruction {
+		r := &ast.AsmInstruction{Opcode: asttest.NewIdent(opcode)}
+		if len(operands) > 0 {
+			r.Operands = ast.AsmOperands(operands)
+		}
+		return r
+	}
+	dir := func(name string, operands ...ast.AsmOperand) *ast.AsmDirective {
+		return &ast.AsmDirective{Ident: asttest.NewIdent(name), Operands: ast.AsmOperands(operands)}
+	}
+
 	runBlock(t,
 		"without variables",
 		[]rune(`
@@ -31,7 +48,7 @@ begin
 		DB     0.99                          { Two bytes }
 		DB     'A'                           { Ord('A') }
 		DB     'Hello world...',0DH,0AH      { String followed by CR/LF }
-		DB     12,'string'                   { {{Delphi}} style string }
+		DB     12,'string'                   (* {{Delphi}} style string *)
 		DW     0FFFFH                        { One word }
 		DW     0,9999                        { Two words }
 		DW   'A'                             { Same as DB  'A',0 }
@@ -51,16 +68,40 @@ end;
 			Body: &ast.CompoundStmt{
 				StmtList: ast.StmtList{
 					&ast.Statement{
-						Body: &ast.AssemblerStatement{},
+						Body: &ast.AssemblerStatement{
+							Statements: ast.AsmStatements{
+								dir("DB", ident("FFH")),
+								dir("DB", num("0.99")),
+								dir("DB", str("'A'")),
+								dir("DB", str("'Hello world...'"), num("0DH"), num("0AH")),
+								dir("DB", num("12"), str("'string'")),
+								dir("DW", num("0FFFFH")),
+								dir("DW", num("0"), num("9999")),
+								dir("DW", str("'A'")),
+								dir("DW", str("'BA'")),
+								dir("DW", ident("MyVar")),
+								dir("DW", ident("MyProc")),
+								dir("DD", num("0FFFFFFFFH")),
+								dir("DD", num("0"), num("999999999")),
+								dir("DD", str("'A'")),
+								dir("DD", str("'DCBA'")),
+								dir("DD", ident("MyVar")),
+								dir("DD", ident("MyProc")),
+							},
+						},
 					},
 				},
 			},
 		},
 	)
 
-	runBlock(t,
-		"with variables", // but they are not ignored
-		[]rune(`
+	{
+		varByte := &ast.VarDecl{IdentList: asttest.NewIdentList("ByteVar"), Type: asttest.NewOrdIdent("Byte")}
+		varWord := &ast.VarDecl{IdentList: asttest.NewIdentList("WordVar"), Type: asttest.NewOrdIdent("Word")}
+		varInt := &ast.VarDecl{IdentList: asttest.NewIdentList("IntVar"), Type: asttest.NewOrdIdent("Integer")}
+		runBlock(t,
+			"with variables",
+			[]rune(`
 var
 	ByteVar: Byte;
 	WordVar: Word;
@@ -70,16 +111,73 @@ asm
 	MOV BX,WordVar
 	MOV ECX,IntVar
 end;
+`),
+			&ast.Block{
+				DeclSections: ast.DeclSections{
+					ast.VarSection{varByte, varWord, varInt},
+				},
+				Body: &ast.AssemblerStatement{
+					Statements: ast.AsmStatements{
+						instr("MOV", reg("AL"), ident("ByteVar", varByte.ToDeclarations()[0])),
+						instr("MOV", reg("BX"), ident("WordVar", varWord.ToDeclarations()[0])),
+						instr("MOV", reg("ECX"), ident("IntVar", varInt.ToDeclarations()[0])),
+					},
+				},
+			},
+		)
+	}
+
+	runBlock(t,
+		"labels, prefixes and operands",
+		[]rune(`
+asm
+	.NOFRAME
+@@loop:     { end of loop is below }
+	PUSH EAX; PUSH EBX
+	REP MOVSB
+	MOV DWORD PTR [EBP-4], $FF
+	MOV EAX, FS:[0]
+	FLD ST(1)
+	LEA ESI, [EBX+ECX*4+8]
+	DEC ECX
+	JNZ @@loop
+	JMP @@end
+@@end:
+end;
 `),
 		&ast.Block{
-			DeclSections: ast.DeclSections{
-				ast.VarSection{
-					&ast.VarDecl{IdentList: asttest.NewIdentList("ByteVar"), Type: asttest.NewOrdIdent("Byte")},
-					&ast.VarDecl{IdentList: asttest.NewIdentList("WordVar"), Type: asttest.NewOrdIdent("Word")},
-					&ast.VarDecl{IdentList: asttest.NewIdentList("IntVar"), Type: asttest.NewOrdIdent("Integer")},
+			Body: &ast.AssemblerStatement{
+				Statements: ast.AsmStatements{
+					dir(".NOFRAME"),
+					&ast.AsmLabel{Ident: asttest.NewIdent("@@loop")},
+					instr("PUSH", reg("EAX")),
+					instr("PUSH", reg("EBX")),
+					&ast.AsmInstruction{Prefix: asttest.NewIdent("REP"), Opcode: asttest.NewIdent("MOVSB")},
+					instr("MOV",
+						&ast.AsmPtr{Size: "DWORD", Operand: &ast.AsmMemoryRef{
+							Operand: &ast.AsmBinaryExpr{Left: reg("EBP"), Op: "-", Right: num("4")},
+						}},
+						num("$FF"),
+					),
+					instr("MOV", reg("EAX"), &ast.AsmMemoryRef{Segment: reg("FS"), Operand: num("0")}),
+					instr("FLD", &ast.AsmRegister{Ident: asttest.NewIdent("ST"), Index: num("1")}),
+					instr("LEA", reg("ESI"), &ast.AsmMemoryRef{
+						Operand: &ast.AsmBinaryExpr{
+							Left: &ast.AsmBinaryExpr{
+								Left:  reg("EBX"),
+								Op:    "+",
+								Right: &ast.AsmBinaryExpr{Left: reg("ECX"), Op: "*", Right: num("4")},
+							},
+							Op:    "+",
+							Right: num("8"),
+						},
+					}),
+					instr("DEC", reg("ECX")),
+					instr("JNZ", ident("@@loop", (&ast.AsmLabel{Ident: asttest.NewIdent("@@loop")}).ToDeclarations()[0])),
+					instr("JMP", ident("@@end", (&ast.AsmLabel{Ident: asttest.NewIdent("@@end")}).ToDeclarations()[0])),
+					&ast.AsmLabel{Ident: asttest.NewIdent("@@end")},
 				},
 			},
-			Body: &ast.AssemblerStatement{},
 		},
 	)
 
@@ -120,8 +218,74 @@ end;
 			FormalParameters: ast.FormalParameters{declE},
 		},
 		Block: &ast.Block{
-			Body: &ast.AssemblerStatement{},
+			Body: &ast.AssemblerStatement{
+				Statements: ast.AsmStatements{
+					instr("MOV", reg("EAX"), ident("e", declE.ToDeclarations()[0])),
+					instr("MOV", reg("EDX"), &ast.AsmMemoryRef{Operand: reg("EAX")}),
+					instr("CALL", &ast.AsmPtr{Size: "DWORD", Operand: &ast.AsmMemoryRef{
+						Operand: &ast.AsmBinaryExpr{
+							Left: reg("EDX"),
+							Op:   "+",
+							Right: &ast.AsmUnaryExpr{Op: "VMTOFFSET", Operand: &ast.AsmBinaryExpr{
+								Left:  ident("TExample"),
+								Op:    ".",
+								Right: ident("VirtualMethod"),
+							}},
+						},
+					}}),
+				},
+			},
 		},
 	})
+}
+
+func TestAssemblerStatementMembers(t *testing.T) {
+	text := []rune(`unit U1;
+
+interface
+
+implementation
+
+type
+  TPoint = record
+    X, Y: Integer;
+  end;
+
+function SumOf(const P: TPoint): Integer;
+asm
+  MOV ECX, [EAX].TPoint.Y
+  ADD ECX, [EAX + TPoint.X]
+  MOV EAX, ECX
+end;
 
+end.
+`)
+
+	parser := parsertest.NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+	recType := unit.ImplementationSection.DeclSections[0].(ast.TypeSection)[0].Type.(*ast.RecType)
+	fieldXY := recType.FieldList.FieldDecls[0]
+	asmStmt := unit.ImplementationSection.DeclSections[1].(*ast.FunctionDecl).Block.Body.(*ast.AssemblerStatement)
+	if !assert.Len(t, asmStmt.Statements, 3) {
+		return
+	}
+
+	// [EAX].TPoint.Y
+	operand1 := asmStmt.Statements[0].(*ast.AsmInstruction).Operands[1].(*ast.AsmBinaryExpr)
+	if typeName := operand1.Left.(*ast.AsmBinaryExpr).Right.(*ast.AsmIdent); assert.NotNil(t, typeName.Ref) {
+		assert.Same(t, unit.ImplementationSection.DeclSections[0].(ast.TypeSection)[0], typeName.Ref.Node)
+	}
+	if member := operand1.Right.(*ast.AsmIdent); assert.NotNil(t, member.Ref) {
+		assert.Same(t, fieldXY, member.Ref.Node)
+	}
+
+	// [EAX + TPoint.X]
+	operand2 := asmStmt.Statements[1].(*ast.AsmInstruction).Operands[1].(*ast.AsmMemoryRef).Operand.(*ast.AsmBinaryExpr)
+	if member := operand2.Right.(*ast.AsmBinaryExpr).Right.(*ast.AsmIdent); assert.NotNil(t, member.Ref) {
+		assert.Same(t, fieldXY, member.Ref.Node)
+	}
 }
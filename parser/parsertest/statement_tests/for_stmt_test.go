@@ -247,4 +247,22 @@ for I := 1 to 10 do
 		},
 	)
 
+	parsertest.RunStatementTest(t,
+		"for in",
+		[]rune(`for C in S do Check(C);`),
+		&ast.Statement{
+			Body: &ast.ForInStmt{
+				QualId:     asttest.NewQualId("C"),
+				Enumerable: asttest.NewExpression(asttest.NewQualId("S")),
+				Statement: &ast.Statement{
+					Body: &ast.CallStatement{
+						Designator: asttest.NewDesignator("Check"),
+						ExprList: ast.ExprList{
+							asttest.NewExpression(asttest.NewQualId("C")),
+						},
+					},
+				},
+			},
+		},
+	)
 }
@@ -0,0 +1,249 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModernClassMembers(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TItem = Integer;
+
+  TBase = class abstract
+  strict private
+    FSecret: Integer;
+  strict protected
+    FShared: Integer;
+  private
+    FHidden: Integer;
+  public
+    type
+      TItem = class
+        Value: Integer;
+      end;
+    const
+      MaxCount = 10;
+    class var
+      FCount: Integer;
+    var
+      FItem: TItem;
+    procedure Run; virtual; abstract;
+  end;
+
+  TChild = class sealed(TBase)
+  public
+    procedure Run; override; final;
+  end;
+
+var
+  Item: TBase.TItem;
+  Outer: TItem;
+
+implementation
+
+procedure TChild.Run;
+var
+  Other: TBase;
+begin
+  FShared := Other.FCount;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+	itemDecl := typeSection[0]
+	baseDecl := typeSection[1]
+	baseType := baseDecl.Type.(*ast.CustomClassType)
+	childType := typeSection[2].Type.(*ast.CustomClassType)
+	if !assert.Len(t, baseType.Members, 4) {
+		return
+	}
+	publicSection := baseType.Members[3]
+
+	t.Run("class types", func(t *testing.T) {
+		assert.True(t, baseType.Abstract)
+		assert.False(t, baseType.Sealed)
+		assert.True(t, childType.Sealed)
+		assert.False(t, childType.Abstract)
+		assert.Equal(t, ast.ClassMethodDirectiveList{ast.CmdOverride, ast.CmdFinal}, childType.Members[0].ClassMethodList[0].Directives)
+	})
+
+	t.Run("member sections", func(t *testing.T) {
+		assert.Equal(t, ast.CvStrictPrivate, baseType.Members[0].Visibility)
+		assert.Equal(t, ast.CvStrictProtected, baseType.Members[1].Visibility)
+		assert.Equal(t, ast.CvPrivate, baseType.Members[2].Visibility)
+
+		assert.Len(t, publicSection.NestedDeclSections, 2)
+		if assert.Len(t, publicSection.ClassFieldList, 2) {
+			assert.True(t, publicSection.ClassFieldList[0].ClassVar)
+			assert.False(t, publicSection.ClassFieldList[1].ClassVar)
+		}
+	})
+
+	nestedItemDecl := publicSection.NestedDeclSections[0].(ast.TypeSection)[0]
+
+	t.Run("nested types", func(t *testing.T) {
+		fieldType := publicSection.ClassFieldList[1].Type.(*ast.TypeId)
+		assert.Same(t, nestedItemDecl, fieldType.Ref.Node)
+
+		varSection := unit.InterfaceSection.InterfaceDecls[1].(ast.VarSection)
+		qualifiedType := varSection[0].Type.(*ast.TypeId)
+		assert.Equal(t, "TBase.TItem", qualifiedType.Ident.Name)
+		assert.Same(t, nestedItemDecl, qualifiedType.Ref.Node)
+
+		outerType := varSection[1].Type.(*ast.TypeId)
+		assert.Same(t, itemDecl, outerType.Ref.Node)
+	})
+
+	t.Run("FindMemberDecl with strict visibility", func(t *testing.T) {
+		inUnit := func(ast.ClassMembersType) bool { return true }
+
+		inBase := &ast.MemberAccess{Type: baseType}
+		assert.NotNil(t, baseType.FindMemberDecl("FSecret", inBase))
+		assert.NotNil(t, baseType.FindMemberDecl("FShared", inBase))
+		assert.NotNil(t, baseType.FindMemberDecl("FHidden", inBase))
+
+		outside := &ast.MemberAccess{}
+		assert.Nil(t, baseType.FindMemberDecl("FSecret", outside))
+		assert.Nil(t, baseType.FindMemberDecl("FShared", outside))
+		assert.Nil(t, baseType.FindMemberDecl("FHidden", outside))
+
+		// Strict members can't be accessed even in the same unit.
+		sameUnit := &ast.MemberAccess{InUnit: inUnit}
+		assert.Nil(t, baseType.FindMemberDecl("FSecret", sameUnit))
+		assert.Nil(t, baseType.FindMemberDecl("FShared", sameUnit))
+		assert.NotNil(t, baseType.FindMemberDecl("FHidden", sameUnit))
+
+		inChild := &ast.MemberAccess{Type: childType}
+		assert.Nil(t, childType.FindMemberDecl("FSecret", inChild))
+		assert.Nil(t, childType.FindMemberDecl("FHidden", inChild))
+		assert.Same(t, baseType.Members[1].ClassFieldList[0], childType.FindMemberDecl("FShared", inChild).Node)
+		assert.Same(t, nestedItemDecl, childType.FindMemberDecl("TItem", inChild).Node)
+
+		inChildInUnit := &ast.MemberAccess{Type: childType, InUnit: inUnit}
+		assert.Nil(t, childType.FindMemberDecl("FSecret", inChildInUnit))
+		assert.NotNil(t, childType.FindMemberDecl("FHidden", inChildInUnit))
+	})
+
+	t.Run("method implementation", func(t *testing.T) {
+		run := unit.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+		assign := run.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		assert.Same(t, baseType.Members[1].ClassFieldList[0], assign.Designator.QualId.Ident.Ref.Node)
+
+		count := assign.Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor).Designator.Items[0].(*ast.DesignatorItemIdent)
+		if assert.NotNil(t, count.Ref) {
+			assert.Same(t, publicSection.ClassFieldList[0], count.Ref.Node)
+		}
+	})
+}
+
+func TestStrictVisibilityInSameUnit(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TBase = class
+  strict private
+    FSecret: Integer;
+  strict protected
+    FShared: Integer;
+  private
+    FHidden: Integer;
+  protected
+    FGuarded: Integer;
+  end;
+
+  TChild = class(TBase)
+    procedure Run;
+  end;
+
+  TOther = class
+    procedure Run;
+  end;
+
+implementation
+
+procedure TChild.Run;
+begin
+  FShared := FGuarded;
+  FHidden := FSecret;
+end;
+
+procedure TOther.Run;
+var
+  Base: TBase;
+begin
+  Base.FSecret := Base.FShared;
+  Base.FHidden := Base.FGuarded;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	baseType := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0].Type.(*ast.CustomClassType)
+	fieldFShared := baseType.Members[1].ClassFieldList[0]
+	fieldFHidden := baseType.Members[2].ClassFieldList[0]
+	fieldFGuarded := baseType.Members[3].ClassFieldList[0]
+
+	assigns := func(decl ast.DeclSection) []*ast.AssignStatement {
+		stmts := decl.(*ast.FunctionDecl).Block.Body.(*ast.CompoundStmt).StmtList
+		r := make([]*ast.AssignStatement, len(stmts))
+		for i, stmt := range stmts {
+			r[i] = stmt.Body.(*ast.AssignStatement)
+		}
+		return r
+	}
+	refOf := func(designator *ast.Designator) *astcore.Decl {
+		if len(designator.Items) == 0 {
+			return designator.QualId.Ident.Ref
+		}
+		return designator.Items[len(designator.Items)-1].(*ast.DesignatorItemIdent).Ref
+	}
+	factorRef := func(expr *ast.Expression) *astcore.Decl {
+		return refOf(expr.SimpleExpression.Term.Factor.(*ast.DesignatorFactor).Designator)
+	}
+
+	t.Run("descendant", func(t *testing.T) {
+		stmts := assigns(unit.ImplementationSection.DeclSections[0])
+		assert.Same(t, fieldFShared, refOf(stmts[0].Designator).Node)
+		assert.Same(t, fieldFGuarded, factorRef(stmts[0].Expression).Node)
+		assert.Same(t, fieldFHidden, refOf(stmts[1].Designator).Node)
+		assert.Nil(t, factorRef(stmts[1].Expression))
+	})
+
+	t.Run("other class in the same unit", func(t *testing.T) {
+		stmts := assigns(unit.ImplementationSection.DeclSections[1])
+		assert.Nil(t, refOf(stmts[0].Designator))
+		assert.Nil(t, factorRef(stmts[0].Expression))
+		assert.Same(t, fieldFHidden, refOf(stmts[1].Designator).Node)
+		assert.Same(t, fieldFGuarded, factorRef(stmts[1].Expression).Node)
+	})
+}
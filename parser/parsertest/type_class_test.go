@@ -772,3 +772,62 @@ type
 		}(),
 	)
 }
+
+func TestClassPropertyNodefaultAndImplements(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	RunTypeSection(t,
+		"nodefault and implements",
+		[]rune(`
+type
+	TFoo = class
+	private
+		FCount: Integer;
+		FBar: IBar;
+	public
+		property Count: Integer read FCount write FCount nodefault;
+		property Bar: IBar read FBar implements IBar;
+	end;
+`),
+		func() ast.TypeSection {
+			// FCount: Integer;
+			fieldDeclFCount := &ast.ClassField{IdentList: asttest.NewIdentList("FCount"), Type: asttest.NewOrdIdent("Integer")}
+			// FBar: IBar;
+			fieldDeclFBar := &ast.ClassField{IdentList: asttest.NewIdentList("FBar"), Type: asttest.NewTypeId("IBar")}
+
+			// property Count: Integer read FCount write FCount nodefault;
+			propertyDeclCount := &ast.ClassProperty{
+				Ident:     asttest.NewIdent("Count"),
+				Interface: &ast.PropertyInterface{Type: asttest.NewOrdIdent("Integer")},
+				Read:      asttest.NewIdentRef("FCount", fieldDeclFCount.ToDeclarations()[0]),
+				Write:     asttest.NewIdentRef("FCount", fieldDeclFCount.ToDeclarations()[0]),
+				Default:   &ast.PropertyDefaultSpecifier{NoDefault: ext.BoolPtr(true)},
+			}
+			// property Bar: IBar read FBar implements IBar;
+			propertyDeclBar := &ast.ClassProperty{
+				Ident:      asttest.NewIdent("Bar"),
+				Interface:  &ast.PropertyInterface{Type: asttest.NewTypeId("IBar")},
+				Read:       asttest.NewIdentRef("FBar", fieldDeclFBar.ToDeclarations()[0]),
+				Implements: asttest.NewTypeId("IBar"),
+			}
+
+			return ast.TypeSection{
+				{
+					Ident: asttest.NewIdent("TFoo"),
+					Type: &ast.CustomClassType{
+						Members: ast.ClassMemberSections{
+							&ast.ClassMemberSection{
+								Visibility:     ast.CvPrivate,
+								ClassFieldList: ast.ClassFieldList{fieldDeclFCount, fieldDeclFBar},
+							},
+							&ast.ClassMemberSection{
+								Visibility:        ast.CvPublic,
+								ClassPropertyList: ast.ClassPropertyList{propertyDeclCount, propertyDeclBar},
+							},
+						},
+					},
+				},
+			}
+		}(),
+	)
+}
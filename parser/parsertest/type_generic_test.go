@@ -0,0 +1,233 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericTypeDecl(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	RunTypeSection(t,
+		"type parameters with constraints",
+		[]rune(`
+type
+  TPair<K, V: class, constructor; W: record> = record
+    Key: K;
+    Value: V;
+  end;
+`),
+		func() ast.TypeSection {
+			constraints := ast.TypeConstraints{ast.CkClass, ast.CkConstructor}
+			paramK := &ast.TypeParam{Ident: asttest.NewIdent("K"), Constraints: constraints}
+			paramV := &ast.TypeParam{Ident: asttest.NewIdent("V"), Constraints: constraints}
+			paramW := &ast.TypeParam{Ident: asttest.NewIdent("W"), Constraints: ast.TypeConstraints{ast.CkRecord}}
+			return ast.TypeSection{
+				&ast.TypeDecl{
+					Ident:      asttest.NewIdent("TPair"),
+					TypeParams: ast.TypeParams{paramK, paramV, paramW},
+					Type: &ast.RecType{
+						FieldList: &ast.FieldList{
+							FieldDecls: ast.FieldDecls{
+								{IdentList: asttest.NewIdentList("Key"), Type: asttest.NewTypeId("K", paramK.ToDeclarations()[0])},
+								{IdentList: asttest.NewIdentList("Value"), Type: asttest.NewTypeId("V", paramV.ToDeclarations()[0])},
+							},
+						},
+					},
+				},
+			}
+		}(),
+	)
+
+	RunTypeSection(t,
+		"interface constraint and specialization",
+		[]rune(`
+type
+  IComparer<T> = interface
+    function Compare(const Left, Right: T): Integer;
+  end;
+  TSorter<T; C: IComparer<T>> = class
+  end;
+  TIntSorter = TSorter<Integer, IComparer<Integer>>;
+`),
+		func() ast.TypeSection {
+			paramT1 := &ast.TypeParam{Ident: asttest.NewIdent("T")}
+			comparerDecl := &ast.TypeDecl{
+				Ident:      asttest.NewIdent("IComparer"),
+				TypeParams: ast.TypeParams{paramT1},
+				Type: &ast.CustomInterfaceType{
+					Members: ast.InterfaceMemberList{
+						&ast.InterfaceMethod{
+							Heading: &ast.FunctionHeading{
+								Type:  ast.FtFunction,
+								Ident: asttest.NewIdent("Compare"),
+								FormalParameters: ast.FormalParameters{{
+									Opt: &ast.FpoConst,
+									Parameter: &ast.Parameter{
+										IdentList: asttest.NewIdentList("Left", "Right"),
+										Type:      &ast.ParameterType{Type: asttest.NewTypeId("T", paramT1.ToDeclarations()[0])},
+									},
+								}},
+								ReturnType: asttest.NewOrdIdent("Integer"),
+							},
+						},
+					},
+				},
+			}
+			paramT2 := &ast.TypeParam{Ident: asttest.NewIdent("T")}
+			comparerOfT := asttest.NewTypeId("IComparer", comparerDecl.ToDeclarations()[0])
+			comparerOfT.TypeArgs = ast.TypeArgs{asttest.NewTypeId("T", paramT2.ToDeclarations()[0])}
+			paramC := &ast.TypeParam{Ident: asttest.NewIdent("C"), Constraints: ast.TypeConstraints{comparerOfT}}
+			sorterDecl := &ast.TypeDecl{
+				Ident:      asttest.NewIdent("TSorter"),
+				TypeParams: ast.TypeParams{paramT2, paramC},
+				Type:       &ast.CustomClassType{},
+			}
+			comparerOfInteger := asttest.NewTypeId("IComparer", comparerDecl.ToDeclarations()[0])
+			comparerOfInteger.TypeArgs = ast.TypeArgs{asttest.NewOrdIdent("Integer")}
+			sorterOfInteger := asttest.NewTypeId("TSorter", sorterDecl.ToDeclarations()[0])
+			sorterOfInteger.TypeArgs = ast.TypeArgs{asttest.NewOrdIdent("Integer"), comparerOfInteger}
+			return ast.TypeSection{
+				comparerDecl,
+				sorterDecl,
+				&ast.TypeDecl{
+					Ident: asttest.NewIdent("TIntSorter"),
+					Type:  sorterOfInteger,
+				},
+			}
+		}(),
+	)
+}
+
+func TestGenericClass(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TList<T> = class
+  private
+    FItems: array of T;
+  public
+    function Get(Index: Integer): T;
+    function Map<U>(const Default: U): TList<U>;
+  end;
+
+implementation
+
+function TList<T>.Get(Index: Integer): T;
+begin
+  Result := FItems[Index];
+end;
+
+function TList<T>.Map<U>(const Default: U): TList<U>;
+var
+  Item: T;
+begin
+  Result := TList<U>.Create;
+  Item := Get(0);
+  Result.Map<T>(Item);
+end;
+
+procedure Compare(A, B: Integer);
+var
+  L: TList<Integer>;
+begin
+  if A < B then
+    L := TList<Integer>.Create;
+  if (A < B) and (B > A) then
+    Exit;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	listDecl := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0]
+	if !assert.Len(t, listDecl.TypeParams, 1) {
+		return
+	}
+	paramT := listDecl.TypeParams[0]
+	listType := listDecl.Type.(*ast.CustomClassType)
+	getMethod := listType.Members[1].ClassMethodList[0]
+	mapMethod := listType.Members[1].ClassMethodList[1]
+
+	t.Run("method declarations", func(t *testing.T) {
+		assert.Same(t, paramT, getMethod.Heading.(*ast.FunctionHeading).ReturnType.Ref.Node)
+
+		mapHeading := mapMethod.Heading.(*ast.FunctionHeading)
+		if assert.Len(t, mapHeading.TypeParams, 1) {
+			paramU := mapHeading.TypeParams[0]
+			assert.Equal(t, "U", paramU.Name)
+			assert.Same(t, listDecl, mapHeading.ReturnType.Ref.Node)
+			if assert.Len(t, mapHeading.ReturnType.TypeArgs, 1) {
+				assert.Same(t, paramU, mapHeading.ReturnType.TypeArgs[0].(*ast.TypeId).Ref.Node)
+			}
+		}
+	})
+
+	declSections := unit.ImplementationSection.DeclSections
+	if !assert.Len(t, declSections, 3) {
+		return
+	}
+
+	t.Run("method implementations", func(t *testing.T) {
+		getImpl := declSections[0].(*ast.FunctionDecl)
+		assert.Same(t, getMethod, getImpl.Method)
+		assert.Same(t, paramT, getImpl.ReturnType.Ref.Node)
+
+		mapImpl := declSections[1].(*ast.FunctionDecl)
+		assert.Same(t, mapMethod, mapImpl.Method)
+		if assert.Len(t, mapImpl.TypeParams, 1) {
+			assert.Equal(t, "U", mapImpl.TypeParams[0].Name)
+		}
+		varSection := mapImpl.Block.DeclSections[0].(ast.VarSection)
+		assert.Same(t, paramT, varSection[0].Type.(*ast.TypeId).Ref.Node)
+
+		stmts := mapImpl.Block.Body.(*ast.CompoundStmt).StmtList
+		create := stmts[0].Body.(*ast.AssignStatement).Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		assert.Same(t, listDecl, create.Designator.QualId.Ident.Ref.Node)
+		if assert.Len(t, create.Designator.Items, 2) {
+			typeArgs := create.Designator.Items[0].(ast.DesignatorItemTypeArgs)
+			assert.Same(t, mapImpl.TypeParams[0], typeArgs[0].(*ast.TypeId).Ref.Node)
+			assert.Equal(t, "Create", create.Designator.Items[1].(*ast.DesignatorItemIdent).Name)
+		}
+
+		call := stmts[2].Body.(*ast.CallStatement)
+		if assert.Len(t, call.Designator.Items, 2) {
+			assert.Equal(t, "Map", call.Designator.Items[0].(*ast.DesignatorItemIdent).Name)
+			typeArgs := call.Designator.Items[1].(ast.DesignatorItemTypeArgs)
+			assert.Same(t, paramT, typeArgs[0].(*ast.TypeId).Ref.Node)
+		}
+	})
+
+	t.Run("relational operators", func(t *testing.T) {
+		stmts := declSections[2].(*ast.FunctionDecl).Block.Body.(*ast.CompoundStmt).StmtList
+
+		ifStmt := stmts[0].Body.(*ast.IfStmt)
+		if assert.Len(t, ifStmt.Condition.RelOpSimpleExpressions, 1) {
+			assert.Equal(t, "<", ifStmt.Condition.RelOpSimpleExpressions[0].RelOp)
+		}
+		create := ifStmt.Then.Body.(*ast.AssignStatement).Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		if assert.Len(t, create.Designator.Items, 2) {
+			typeArgs := create.Designator.Items[0].(ast.DesignatorItemTypeArgs)
+			asttest.ClearLocations(t, typeArgs)
+			assert.Equal(t, ast.DesignatorItemTypeArgs{asttest.NewOrdIdent("Integer")}, typeArgs)
+		}
+
+		ifStmt2 := stmts[1].Body.(*ast.IfStmt)
+		assert.IsType(t, &ast.Parentheses{}, ifStmt2.Condition.SimpleExpression.Term.Factor)
+	})
+}
@@ -0,0 +1,66 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+)
+
+func TestHelperType(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	RunTypeSection(t,
+		"class helper with ancestor helper and record helper",
+		[]rune(`
+type
+  TFoo = class
+  end;
+  TFooHelper = class helper for TFoo
+    procedure Bar;
+  end;
+  TFooHelperEx = class helper(TFooHelper) for TFoo
+  end;
+  TIntHelper = record helper for Integer
+  end;
+`),
+		func() ast.TypeSection {
+			fooDecl := &ast.TypeDecl{Ident: asttest.NewIdent("TFoo"), Type: &ast.CustomClassType{}}
+			fooHelperDecl := &ast.TypeDecl{
+				Ident: asttest.NewIdent("TFooHelper"),
+				Type: &ast.HelperType{
+					Kind: ast.HkClass,
+					For:  asttest.NewTypeId("TFoo", fooDecl.ToDeclarations()[0]),
+					Members: ast.ClassMemberSections{
+						{
+							Visibility: ast.CvDefault,
+							ClassMethodList: ast.ClassMethodList{
+								{Heading: &ast.FunctionHeading{Type: ast.FtProcedure, Ident: asttest.NewIdent("Bar")}},
+							},
+						},
+					},
+				},
+			}
+			return ast.TypeSection{
+				fooDecl,
+				fooHelperDecl,
+				&ast.TypeDecl{
+					Ident: asttest.NewIdent("TFooHelperEx"),
+					Type: &ast.HelperType{
+						Kind:     ast.HkClass,
+						Heritage: ast.ClassHeritage{asttest.NewTypeId("TFooHelper", fooHelperDecl.ToDeclarations()[0])},
+						For:      asttest.NewTypeId("TFoo", fooDecl.ToDeclarations()[0]),
+					},
+				},
+				&ast.TypeDecl{
+					Ident: asttest.NewIdent("TIntHelper"),
+					Type: &ast.HelperType{
+						Kind: ast.HkRecord,
+						For:  asttest.NewOrdIdent("Integer"),
+					},
+				},
+			}
+		}(),
+	)
+}
@@ -0,0 +1,171 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/asttest"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterfaceType(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	RunTypeSection(t,
+		"interface with GUID, heritage, methods and property",
+		[]rune(`
+type
+  IStream = interface(IUnknown)
+    ['{0000000C-0000-0000-C000-000000000046}']
+    function GetSize: Integer; stdcall;
+    procedure SetSize(Value: Integer); safecall;
+    property Size: Integer read GetSize write SetSize;
+  end;
+`),
+		func() ast.TypeSection {
+			getSize := &ast.InterfaceMethod{
+				Heading: &ast.FunctionHeading{
+					Type:       ast.FtFunction,
+					Ident:      asttest.NewIdent("GetSize"),
+					ReturnType: asttest.NewOrdIdent("Integer"),
+				},
+				Directives: ast.InterfaceMethodDirectives{ast.ImdStdcall},
+			}
+			setSize := &ast.InterfaceMethod{
+				Heading: &ast.FunctionHeading{
+					Type:  ast.FtProcedure,
+					Ident: asttest.NewIdent("SetSize"),
+					FormalParameters: ast.FormalParameters{{
+						Parameter: &ast.Parameter{
+							IdentList: asttest.NewIdentList("Value"),
+							Type:      &ast.ParameterType{Type: asttest.NewOrdIdent("Integer")},
+						},
+					}},
+				},
+				Directives: ast.InterfaceMethodDirectives{ast.ImdSafecall},
+			}
+			return ast.TypeSection{
+				&ast.TypeDecl{
+					Ident: asttest.NewIdent("IStream"),
+					Type: &ast.CustomInterfaceType{
+						Heritage: ast.InterfaceHeritage{asttest.NewTypeId("IUnknown")},
+						Guid: &ast.InterfaceGuid{
+							ConstExpr: asttest.NewConstExpr(asttest.NewString("'{0000000C-0000-0000-C000-000000000046}'")),
+						},
+						Members: ast.InterfaceMemberList{
+							getSize,
+							setSize,
+							&ast.InterfaceProperty{
+								Ident:     asttest.NewIdent("Size"),
+								Interface: &ast.PropertyInterface{Type: asttest.NewOrdIdent("Integer")},
+								Read:      asttest.NewIdentRef("GetSize", getSize.ToDeclarations()[0]),
+								Write:     asttest.NewIdentRef("SetSize", setSize.ToDeclarations()[0]),
+							},
+						},
+					},
+				},
+			}
+		}(),
+	)
+
+	RunTypeSection(t,
+		"empty interface",
+		[]rune(`
+type
+  IEmpty = interface
+  end;
+`),
+		ast.TypeSection{
+			&ast.TypeDecl{
+				Ident: asttest.NewIdent("IEmpty"),
+				Type:  &ast.CustomInterfaceType{},
+			},
+		},
+	)
+}
+
+func TestInterfaceTypeDeclarations(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`
+type
+  IChild = interface;
+
+  IParent = interface
+    ['{11111111-2222-3333-4444-555555555555}']
+    function GetChild(Index: Integer): IChild;
+    property Children[Index: Integer]: IChild read GetChild; default;
+  end;
+
+  IChild = interface(IParent)
+    function GetName: string;
+    property Name: string read GetName;
+  end;
+
+  IDispatchable = dispinterface
+    ['{66666666-7777-8888-9999-000000000000}']
+    procedure Run; dispid 1;
+    property Count: Integer readonly dispid 2;
+  end;
+`)
+	ctx := NewTestUnitContext()
+	p := NewTestParser(&text, ctx)
+	p.NextToken()
+	section, err := p.ParseTypeSection(true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, section, 4) {
+		return
+	}
+
+	// Forward declared interface is resolved by the actual declaration
+	fwd, ok := section[0].Type.(*ast.ForwardDeclaredInterfaceType)
+	if !assert.True(t, ok) {
+		return
+	}
+	child, ok := section[2].Type.(*ast.CustomInterfaceType)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Same(t, child, fwd.Actual)
+
+	// Interfaces are registered in the DeclMap
+	if decl := ctx.Get("IChild"); assert.NotNil(t, decl) {
+		assert.Same(t, section[2], decl.Node)
+	}
+	if decl := ctx.Get("IDispatchable"); assert.NotNil(t, decl) {
+		assert.Same(t, section[3], decl.Node)
+	}
+
+	parent := section[1].Type.(*ast.CustomInterfaceType)
+	children := parent.Members[1].(*ast.InterfaceProperty)
+	assert.True(t, children.Default)
+	if assert.NotNil(t, children.Interface) {
+		assert.Len(t, children.Interface.Parameters, 1)
+	}
+
+	// Members of the parent interface can be found through the child interface
+	assert.Equal(t, []*ast.CustomInterfaceType{parent}, child.GetParentInterfaces())
+	if decl := child.FindMemberDecl("getchild"); assert.NotNil(t, decl) {
+		assert.Same(t, parent.Members[0], decl.Node)
+	}
+	if decl := child.FindMemberDecl("Name"); assert.NotNil(t, decl) {
+		assert.Same(t, child.Members[1], decl.Node)
+	}
+	assert.Nil(t, child.FindMemberDecl("Unknown"))
+	assert.Len(t, child.MemberDecls(), 4)
+
+	dispintf, ok := section[3].Type.(*ast.CustomInterfaceType)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, dispintf.DispInterface)
+	run := dispintf.Members[0].(*ast.InterfaceMethod)
+	assert.Equal(t, ast.InterfaceMethodDirectives{ast.ImdDispId}, run.Directives)
+	assert.Equal(t, asttest.NewConstExpr(asttest.NewNumber("1")), run.DispId)
+	count := dispintf.Members[1].(*ast.InterfaceProperty)
+	assert.True(t, count.ReadOnly)
+	assert.Equal(t, asttest.NewConstExpr(asttest.NewNumber("2")), count.DispId)
+}
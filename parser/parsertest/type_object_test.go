@@ -0,0 +1,126 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectType(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TPoint = object
+    X, Y: Integer;
+    constructor Init(AX, AY: Integer);
+    procedure Show; virtual;
+  end;
+  TCircle = object(TPoint)
+  private
+    FRadius: Integer;
+  public
+    procedure Show; virtual;
+    property Radius: Integer read FRadius;
+  end;
+  TEmpty = object
+  end;
+
+implementation
+
+constructor TPoint.Init(AX, AY: Integer);
+begin
+  X := AX;
+end;
+
+procedure TPoint.Show;
+begin
+end;
+
+procedure TCircle.Show;
+begin
+  Y := Self.FRadius;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	typeSection := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)
+	pointDecl, circleDecl := typeSection[0], typeSection[1]
+	point := pointDecl.Type.(*ast.CustomObjectType)
+	circle := circleDecl.Type.(*ast.CustomObjectType)
+	assert.Equal(t, &ast.CustomObjectType{}, typeSection[2].Type)
+
+	fieldX := point.Members[0].ClassFieldList[0]
+	fieldFRadius := circle.Members[0].ClassFieldList[0]
+
+	t.Run("heritage", func(t *testing.T) {
+		assert.Nil(t, point.GetParentObject())
+		assert.Same(t, point, circle.GetParentObject())
+	})
+
+	t.Run("members", func(t *testing.T) {
+		if assert.Len(t, point.Members[0].ClassMethodList, 2) {
+			assert.IsType(t, &ast.ConstructorHeading{}, point.Members[0].ClassMethodList[0].Heading)
+			assert.Equal(t, ast.ClassMethodDirectiveList{ast.CmdVirtual}, point.Members[0].ClassMethodList[1].Directives)
+		}
+		assert.Same(t, fieldFRadius, circle.Members[1].ClassPropertyList[0].Read.Ref.Node)
+
+		outside := &ast.MemberAccess{}
+		if decl := circle.FindMemberDecl("Init", outside); assert.NotNil(t, decl) {
+			assert.Same(t, point.Members[0].ClassMethodList[0], decl.Node)
+		}
+		if decl := circle.FindMemberDecl("Show", outside); assert.NotNil(t, decl) {
+			assert.Same(t, circle.Members[1].ClassMethodList[0], decl.Node)
+		}
+		assert.Nil(t, circle.FindMemberDecl("FRadius", outside))
+		assert.Len(t, circle.MemberDecls(&ast.MemberAccess{Type: circle}), 7)
+	})
+
+	declSections := unit.ImplementationSection.DeclSections
+	if !assert.Len(t, declSections, 3) {
+		return
+	}
+
+	t.Run("constructor", func(t *testing.T) {
+		m := declSections[0].(*ast.FunctionDecl)
+		assert.Same(t, pointDecl, m.ClassTypes[0].Ref.Node)
+		assert.Same(t, point.Members[0].ClassMethodList[0], m.Method)
+
+		stmt := m.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+			assert.Same(t, fieldX, stmt.Designator.QualId.Ident.Ref.Node)
+		}
+	})
+
+	t.Run("method of descendant", func(t *testing.T) {
+		m := declSections[2].(*ast.FunctionDecl)
+		assert.Same(t, circleDecl, m.ClassTypes[0].Ref.Node)
+		assert.Same(t, circle.Members[1].ClassMethodList[0], m.Method)
+
+		stmt := m.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+			assert.Same(t, fieldX, stmt.Designator.QualId.Ident.Ref.Node.(*ast.ClassField))
+			assert.Equal(t, "Y", stmt.Designator.QualId.Ident.Name)
+		}
+		factor := stmt.Expression.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		if assert.NotNil(t, factor.Designator.QualId.Ident.Ref) {
+			self, ok := factor.Designator.QualId.Ident.Ref.Node.(*ast.Self)
+			if assert.True(t, ok) {
+				assert.Same(t, circleDecl, self.TypeDecl)
+			}
+		}
+	})
+}
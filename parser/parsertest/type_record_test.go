@@ -0,0 +1,129 @@
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/log/testlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordWithMembers(t *testing.T) {
+	defer testlog.Setup(t)()
+
+	text := []rune(`unit U1;
+
+interface
+
+type
+  TVec = record
+  private
+    const
+      Zero = 0;
+    type
+      TItems = array[0..1] of Integer;
+    var
+      FX, FY: Integer;
+    function GetLength: Integer;
+  public
+    constructor Create(AX, AY: Integer);
+    class operator Add(const A, B: TVec): TVec;
+    property X: Integer read FX write FX;
+    property Length: Integer read GetLength;
+  end;
+
+implementation
+
+constructor TVec.Create(AX, AY: Integer);
+begin
+  FX := AX;
+  FY := AY + Zero;
+end;
+
+function TVec.GetLength: Integer;
+begin
+  Result := FX + FY;
+end;
+
+class operator TVec.Add(const A, B: TVec): TVec;
+begin
+  Result.FX := A.FX + B.FX;
+end;
+
+end.
+`)
+
+	parser := NewTestUnitParser(&text)
+	parser.NextToken()
+	unit, err := parser.ParseUnit()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	vecDecl := unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0]
+	vecType, ok := vecDecl.Type.(*ast.RecType)
+	if !assert.True(t, ok) || !assert.Len(t, vecType.Members, 2) {
+		return
+	}
+	privateSection := vecType.Members[0]
+	publicSection := vecType.Members[1]
+
+	t.Run("member sections", func(t *testing.T) {
+		assert.Equal(t, ast.CvPrivate, privateSection.Visibility)
+		if assert.Len(t, privateSection.NestedDeclSections, 2) {
+			assert.IsType(t, ast.ConstSection{}, privateSection.NestedDeclSections[0])
+			assert.IsType(t, ast.TypeSection{}, privateSection.NestedDeclSections[1])
+		}
+		assert.Len(t, privateSection.ClassFieldList, 1)
+		assert.Len(t, privateSection.ClassMethodList, 1)
+
+		assert.Equal(t, ast.CvPublic, publicSection.Visibility)
+		if assert.Len(t, publicSection.ClassMethodList, 2) {
+			assert.IsType(t, &ast.ConstructorHeading{}, publicSection.ClassMethodList[0].Heading)
+			operator, ok := publicSection.ClassMethodList[1].Heading.(*ast.OperatorHeading)
+			if assert.True(t, ok) {
+				assert.Equal(t, "Add", operator.Name)
+				assert.Len(t, operator.FormalParameters, 1)
+				assert.Same(t, vecDecl, operator.ReturnType.Ref.Node)
+			}
+		}
+		if assert.Len(t, publicSection.ClassPropertyList, 2) {
+			fieldFX := privateSection.ClassFieldList[0]
+			assert.Same(t, fieldFX, publicSection.ClassPropertyList[0].Read.Ref.Node)
+			assert.Same(t, privateSection.ClassMethodList[0], publicSection.ClassPropertyList[1].Read.Ref.Node)
+		}
+	})
+
+	t.Run("FindMemberDecl", func(t *testing.T) {
+		inside := &ast.MemberAccess{Type: vecType}
+		outside := &ast.MemberAccess{}
+		assert.Same(t, privateSection.ClassFieldList[0], vecType.FindMemberDecl("FY", inside).Node)
+		assert.Nil(t, vecType.FindMemberDecl("FY", outside))
+		assert.NotNil(t, vecType.FindMemberDecl("Zero", inside))
+		assert.NotNil(t, vecType.FindMemberDecl("TItems", inside))
+		assert.Same(t, publicSection.ClassMethodList[1], vecType.FindMemberDecl("Add", outside).Node)
+		assert.Same(t, publicSection.ClassPropertyList[1], vecType.FindMemberDecl("Length", outside).Node)
+	})
+
+	declSections := unit.ImplementationSection.DeclSections
+	if !assert.Len(t, declSections, 3) {
+		return
+	}
+
+	t.Run("method implementations", func(t *testing.T) {
+		create := declSections[0].(*ast.FunctionDecl)
+		assert.Equal(t, ast.FtConstructor, create.Type)
+		assert.Same(t, publicSection.ClassMethodList[0], create.Method)
+		stmts := create.Block.Body.(*ast.CompoundStmt).StmtList
+		assign := stmts[0].Body.(*ast.AssignStatement)
+		assert.Same(t, privateSection.ClassFieldList[0], assign.Designator.QualId.Ident.Ref.Node)
+
+		getLength := declSections[1].(*ast.FunctionDecl)
+		assert.Same(t, privateSection.ClassMethodList[0], getLength.Method)
+
+		add := declSections[2].(*ast.FunctionDecl)
+		assert.Equal(t, ast.FtOperator, add.Type)
+		assert.Same(t, publicSection.ClassMethodList[1], add.Method)
+		assert.Same(t, vecDecl, add.ReturnType.Ref.Node)
+	})
+}
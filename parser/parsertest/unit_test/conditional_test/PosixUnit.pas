@@ -0,0 +1,10 @@
+unit PosixUnit;
+
+interface
+
+const
+  LineBreakSize = 1;
+
+implementation
+
+end.
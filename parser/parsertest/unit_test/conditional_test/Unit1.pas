@@ -0,0 +1,38 @@
+unit Unit1;
+
+interface
+
+{$DEFINE UNIT1_LOCAL}
+
+{$IF CompilerVersion >= 20.0}
+const
+  StringKind = 'Unicode';
+{$ELSE}
+const
+  StringKind = 'Ansi';
+{$IFEND}
+
+procedure Run;
+
+implementation
+
+{$IF Defined(MSWINDOWS) and not Defined(DEBUG)}
+const
+  Platform = 'Windows';
+{$ELSEIF Defined(MSWINDOWS)}
+const
+  Platform = 'Windows (debug)';
+{$ELSE}
+const
+  Platform = 'Posix';
+{$IFEND}
+
+{$IF Declared(Platform)}
+procedure Run;
+begin
+end;
+{$ELSE}
+procedure Run; forward;
+{$IFEND}
+
+end.
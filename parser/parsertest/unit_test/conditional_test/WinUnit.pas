@@ -0,0 +1,10 @@
+unit WinUnit;
+
+interface
+
+const
+  LineBreakSize = 2;
+
+implementation
+
+end.
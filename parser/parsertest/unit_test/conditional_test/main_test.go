@@ -0,0 +1,77 @@
+package conditional_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalCompilation(t *testing.T) {
+	type pattern struct {
+		name       string
+		defines    parser.Defines
+		constants  parser.Constants
+		unitName   string
+		platform   string
+		stringKind string
+	}
+
+	patterns := []pattern{
+		{"MSWINDOWS", parser.NewDefines("MSWINDOWS"), nil, "WinUnit", "'Windows'", "'Unicode'"},
+		{"MSWINDOWS and DEBUG", parser.NewDefines("MSWINDOWS", "DEBUG"), nil, "WinUnit", "'Windows (debug)'", "'Unicode'"},
+		{"no defines", nil, nil, "PosixUnit", "'Posix'", "'Unicode'"},
+		{"old compiler", nil, parser.Constants{"CompilerVersion": 18.5}, "PosixUnit", "'Posix'", "'Ansi'"},
+	}
+
+	for _, ptn := range patterns {
+		t.Run(ptn.name, func(t *testing.T) {
+			args := []interface{}{}
+			if ptn.defines != nil {
+				args = append(args, ptn.defines)
+			}
+			if ptn.constants != nil {
+				args = append(args, ptn.constants)
+			}
+			actualProg, err := parser.ParseProgram("Project1.dpr", args...)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			usesClause := actualProg.ProgramBlock.UsesClause
+			if !assert.Len(t, usesClause, 2) {
+				return
+			}
+			assert.Equal(t, ptn.unitName, usesClause[0].Ident.Name)
+			assert.Equal(t, "Unit1", usesClause[1].Ident.Name)
+
+			unit1 := actualProg.Units.ByName("Unit1")
+			if !assert.NotNil(t, unit1) {
+				return
+			}
+			intfDecls := unit1.InterfaceSection.InterfaceDecls
+			if assert.Len(t, intfDecls, 2) {
+				constSection, ok := intfDecls[0].(ast.ConstSection)
+				if assert.True(t, ok) && assert.Len(t, constSection, 1) {
+					assert.Equal(t, ast.NewConstExpr(ast.NewString(ptn.stringKind)), constSection[0].ConstExpr)
+				}
+			}
+
+			decls := unit1.ImplementationSection.DeclSections
+			if !assert.Len(t, decls, 2) {
+				return
+			}
+			constSection, ok := decls[0].(ast.ConstSection)
+			if assert.True(t, ok) && assert.Len(t, constSection, 1) {
+				assert.Equal(t, "Platform", constSection[0].Ident.Name)
+				assert.Equal(t, ast.NewConstExpr(ast.NewString(ptn.platform)), constSection[0].ConstExpr)
+			}
+			run, ok := decls[1].(*ast.FunctionDecl)
+			if assert.True(t, ok) {
+				// Declared(Platform) is true, so Run has its body
+				assert.NotNil(t, run.Block)
+			}
+		})
+	}
+}
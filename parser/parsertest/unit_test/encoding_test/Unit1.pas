@@ -0,0 +1,10 @@
+unit Unit1;
+
+interface
+
+const
+  Name1 = 'Caf';
+
+implementation
+
+end.
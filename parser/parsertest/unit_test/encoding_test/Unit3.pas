@@ -0,0 +1,10 @@
+unit Unit3;
+
+interface
+
+const
+  Name3 = '{';
+
+implementation
+
+end.
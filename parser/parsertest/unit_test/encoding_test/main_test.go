@@ -0,0 +1,55 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodings(t *testing.T) {
+	stringConstant := func(decls ast.DeclSections) string {
+		constDecl := decls[0].(ast.ConstSection)[0]
+		return constDecl.ConstExpr.SimpleExpression.Term.Factor.(*ast.StringFactor).Value
+	}
+	intfStringConstant := func(unit *ast.Unit) string {
+		constDecl := unit.InterfaceSection.InterfaceDecls[0].(ast.ConstSection)[0]
+		return constDecl.ConstExpr.SimpleExpression.Term.Factor.(*ast.StringFactor).Value
+	}
+
+	t.Run("BOM, per-file override and default encoding", func(t *testing.T) {
+		actualProg, err := parser.ParseProgram("Project1.dpr",
+			parser.FileEncodings{"Unit1.pas": parser.Windows1252},
+		)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// UTF-8 with BOM
+		assert.Equal(t, "'こんにちは'", stringConstant(actualProg.ProgramBlock.Block.DeclSections))
+
+		if !assert.Len(t, actualProg.Units, 3) {
+			return
+		}
+		// Windows-1252 given by FileEncodings
+		assert.Equal(t, "'Café'", intfStringConstant(actualProg.Units.ByName("Unit1")))
+		// UTF-16LE with BOM
+		assert.Equal(t, "'ünïcödé'", intfStringConstant(actualProg.Units.ByName("Unit2")))
+		// Shift_JIS by default
+		assert.Equal(t, "'日本語'", intfStringConstant(actualProg.Units.ByName("Unit3")))
+	})
+
+	t.Run("BOM takes precedence over given encoding", func(t *testing.T) {
+		actualProg, err := parser.ParseProgram("Project1.dpr",
+			parser.Windows1252,
+			parser.FileEncodings{"Unit3.pas": parser.ShiftJIS},
+		)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "'こんにちは'", stringConstant(actualProg.ProgramBlock.Block.DeclSections))
+		assert.Equal(t, "'Café'", intfStringConstant(actualProg.Units.ByName("Unit1")))
+		assert.Equal(t, "'ünïcödé'", intfStringConstant(actualProg.Units.ByName("Unit2")))
+		assert.Equal(t, "'日本語'", intfStringConstant(actualProg.Units.ByName("Unit3")))
+	})
+}
@@ -0,0 +1,31 @@
+unit Helpers1;
+
+interface
+
+uses
+  Strs;
+
+type
+  TStringsHelper1 = class helper for TStrings
+  public
+    function Describe: string;
+  end;
+
+  TStringHelper = record helper for string
+    function Upper: string;
+  end;
+
+implementation
+
+function TStringsHelper1.Describe: string;
+begin
+  if Count > 0 then
+    Result := 'Helper1';
+end;
+
+function TStringHelper.Upper: string;
+begin
+  Result := Self;
+end;
+
+end.
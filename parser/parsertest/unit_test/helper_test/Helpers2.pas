@@ -0,0 +1,20 @@
+unit Helpers2;
+
+interface
+
+uses
+  Strs;
+
+type
+  TStringsHelper2 = class helper for TStrings
+    function Describe: string;
+  end;
+
+implementation
+
+function TStringsHelper2.Describe: string;
+begin
+  Result := 'Helper2';
+end;
+
+end.
@@ -0,0 +1,21 @@
+unit Strs;
+
+interface
+
+type
+  TStrings = class
+  public
+    function Count: Integer;
+  end;
+
+  TStringList = class(TStrings)
+  end;
+
+implementation
+
+function TStrings.Count: Integer;
+begin
+  Result := 0;
+end;
+
+end.
@@ -0,0 +1,20 @@
+unit Unit1;
+
+interface
+
+uses
+  Strs;
+
+procedure Run(List: TStrings);
+
+implementation
+
+uses
+  Helpers2, Helpers1;
+
+procedure Run(List: TStrings);
+begin
+  Writeln(List.Describe);
+end;
+
+end.
@@ -0,0 +1,82 @@
+package helper_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpers(t *testing.T) {
+	actualProg, err := parser.ParseProgram("Project1.dpr")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	strs := actualProg.Units.ByName("Strs")
+	helpers1 := actualProg.Units.ByName("Helpers1")
+	helpers2 := actualProg.Units.ByName("Helpers2")
+	unit1 := actualProg.Units.ByName("Unit1")
+	if !assert.NotNil(t, strs) || !assert.NotNil(t, helpers1) || !assert.NotNil(t, helpers2) || !assert.NotNil(t, unit1) {
+		return
+	}
+
+	stringsDecl := strs.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[0]
+	countMethod := stringsDecl.Type.(*ast.CustomClassType).Members[0].ClassMethodList[0]
+
+	helperTypeOf := func(unit *ast.Unit, index int) *ast.HelperType {
+		return unit.InterfaceSection.InterfaceDecls[0].(ast.TypeSection)[index].Type.(*ast.HelperType)
+	}
+	stringsHelper1 := helperTypeOf(helpers1, 0)
+	stringHelper := helperTypeOf(helpers1, 1)
+	stringsHelper2 := helperTypeOf(helpers2, 0)
+
+	t.Run("helper types", func(t *testing.T) {
+		assert.Equal(t, ast.HkClass, stringsHelper1.Kind)
+		assert.Same(t, stringsDecl, stringsHelper1.For.Ref.Node)
+
+		assert.Equal(t, ast.HkRecord, stringHelper.Kind)
+		assert.Equal(t, "string", stringHelper.For.Ident.Name)
+		assert.NotNil(t, stringHelper.For.Ref)
+	})
+
+	t.Run("helper method implementation", func(t *testing.T) {
+		describe := helpers1.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+		assert.Same(t, stringsHelper1.Members[0].ClassMethodList[0], describe.Method)
+		ifStmt := describe.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.IfStmt)
+		count := ifStmt.Condition.SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		assert.Same(t, countMethod, count.Designator.QualId.Ident.Ref.Node)
+	})
+
+	memberRefOf := func(stmt *ast.Statement) *ast.DesignatorItemIdent {
+		call := stmt.Body.(*ast.CallStatement)
+		factor := call.ExprList[0].SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+		return factor.Designator.Items[0].(*ast.DesignatorItemIdent)
+	}
+
+	t.Run("last helper in scope wins", func(t *testing.T) {
+		stmts := actualProg.ProgramBlock.Body.(*ast.CompoundStmt).StmtList
+
+		describe := memberRefOf(stmts[0])
+		if assert.NotNil(t, describe.Ref) {
+			assert.Same(t, stringsHelper2.Members[0].ClassMethodList[0], describe.Ref.Node)
+		}
+
+		count := memberRefOf(stmts[1])
+		if assert.NotNil(t, count.Ref) {
+			assert.Same(t, countMethod, count.Ref.Node)
+		}
+
+		upper := memberRefOf(stmts[2])
+		if assert.NotNil(t, upper.Ref) {
+			assert.Same(t, stringHelper.Members[0].ClassMethodList[0], upper.Ref.Node)
+		}
+
+		run := unit1.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+		describeInUnit1 := memberRefOf(run.Block.Body.(*ast.CompoundStmt).StmtList[0])
+		if assert.NotNil(t, describeInUnit1.Ref) {
+			assert.Same(t, stringsHelper1.Members[0].ClassMethodList[0], describeInUnit1.Ref.Node)
+		}
+	})
+}
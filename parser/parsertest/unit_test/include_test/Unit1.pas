@@ -0,0 +1,20 @@
+unit Unit1;
+
+interface
+
+{$I settings.inc}
+
+{$IFDEF USE_UNIT1}
+{$INCLUDE 'shared.inc'}
+{$ENDIF}
+
+procedure Run;
+
+implementation
+
+procedure Run;
+begin
+  Counter := MaxCount;
+end;
+
+end.
@@ -0,0 +1,113 @@
+package include_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludeFiles(t *testing.T) {
+	t.Run("with include search path", func(t *testing.T) {
+		actualProg, err := parser.ParseProgram("Project1.dpr", parser.IncludeSearchPath{"inc"})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		unit1 := actualProg.Units.ByName("Unit1")
+		if !assert.NotNil(t, unit1) {
+			return
+		}
+		decls := unit1.InterfaceSection.InterfaceDecls
+		if !assert.Len(t, decls, 3) {
+			return
+		}
+
+		constSection, ok := decls[0].(ast.ConstSection)
+		if assert.True(t, ok) && assert.Len(t, constSection, 1) {
+			maxCount := constSection[0]
+			assert.Equal(t, "MaxCount", maxCount.Ident.Name)
+			// The location points at the included file
+			assert.Equal(t, filepath.Join("inc", "shared.inc"), maxCount.Ident.Location.Path)
+			assert.Equal(t, 2, maxCount.Ident.Location.Start.Line)
+			assert.Equal(t, 3, maxCount.Ident.Location.Start.Col)
+		}
+		varSection, ok := decls[1].(ast.VarSection)
+		if assert.True(t, ok) && assert.Len(t, varSection, 1) {
+			assert.Equal(t, "Counter", varSection[0].IdentList[0].Name)
+			assert.Equal(t, filepath.Join("inc", "shared.inc"), varSection[0].IdentList[0].Location.Path)
+		}
+
+		// Identifiers declared in the include file are resolved
+		run, ok := unit1.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+		if !assert.True(t, ok) {
+			return
+		}
+		stmt, ok := run.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.AssignStatement)
+		if !assert.True(t, ok) {
+			return
+		}
+		if assert.NotNil(t, stmt.Designator.QualId.Ident.Ref) {
+			assert.Equal(t, filepath.Join("inc", "shared.inc"), stmt.Designator.QualId.Ident.Ref.Location.Path)
+		}
+	})
+
+	t.Run("without include search path", func(t *testing.T) {
+		// shared.inc is not found, so Unit1 can't be compiled
+		_, err := parser.ParseProgram("Project1.dpr")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to include shared.inc at 8:1")
+			assert.Contains(t, err.Error(), "include file shared.inc not found")
+		}
+	})
+
+	t.Run("lossless", func(t *testing.T) {
+		newParser := func(lossless bool) *parser.UnitParser {
+			prog := parser.NewProgramContext(parser.IncludeSearchPath{"inc"}, parser.Lossless(lossless))
+			p := parser.NewUnitParser(parser.NewUnitContext(prog, "Unit1.pas"))
+			if !assert.NoError(t, p.LoadFile()) {
+				t.FailNow()
+			}
+			return p
+		}
+
+		p := newParser(true)
+		tokens := []*token.Token{p.CurrentToken()}
+		for tokens[len(tokens)-1].Type != token.EOF {
+			tokens = append(tokens, p.NextToken())
+		}
+
+		readFile := func(path string) string {
+			b, err := ioutil.ReadFile(path)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			return string(b)
+		}
+		assert.Equal(t, readFile("Unit1.pas"), token.SourceText(tokens))
+
+		// settings.inc has no token to keep its text
+		sharedPath := filepath.Join("inc", "shared.inc")
+		var shared strings.Builder
+		for _, tk := range tokens {
+			if tk.Start.Path == sharedPath {
+				shared.WriteString(tk.FullString())
+			}
+		}
+		assert.Equal(t, readFile(sharedPath), shared.String())
+
+		expected, err := newParser(false).ParseUnit()
+		if !assert.NoError(t, err) {
+			return
+		}
+		actual, err := newParser(true).ParseUnit()
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, actual)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+unit Calc;
+
+interface
+
+function Add(A, B: Integer): Integer; stdcall;
+function Sub(A, B: Integer): Integer; stdcall;
+
+implementation
+
+function Add(A, B: Integer): Integer;
+begin
+  Result := A + B;
+end;
+
+function Sub(A, B: Integer): Integer;
+begin
+  Result := A - B;
+end;
+
+end.
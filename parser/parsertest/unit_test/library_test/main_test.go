@@ -0,0 +1,46 @@
+package library_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibrary(t *testing.T) {
+	actual, err := parser.ParseLibrary("MyLib.dpr")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "MyLib", actual.Ident.Name)
+	assert.Equal(t, "MyLib.dpr", actual.GetPath())
+
+	if !assert.Len(t, actual.Units, 1) {
+		return
+	}
+	calc := actual.Units[0]
+	assert.Equal(t, "Calc", calc.Ident.Name)
+	assert.Same(t, calc, actual.ProgramBlock.UsesClause.Find("Calc").Unit)
+	assert.Equal(t,
+		[]*parser.UnitNotFoundError{{UnitName: "SysUtils", SearchedDirs: []string{"."}}},
+		actual.MissingUnits,
+	)
+
+	// The library is registered in its DeclMap like a program
+	if assert.NotNil(t, actual.DeclMap) {
+		assert.Same(t, actual.Library, actual.DeclMap.Get("MyLib").Node)
+	}
+
+	items := actual.ExportsItems()
+	if !assert.Len(t, items, 2) {
+		return
+	}
+	assert.Equal(t, "Add", items[0].Ident.Name)
+	assert.Equal(t, ast.NewConstExpr(ast.NewString("'CalcAdd'")), items[0].Name)
+	assert.Nil(t, items[0].Index)
+	assert.Equal(t, "Sub", items[1].Ident.Name)
+	assert.Nil(t, items[1].Name)
+	assert.Equal(t, ast.NewConstExpr(ast.NewNumber("2")), items[1].Index)
+}
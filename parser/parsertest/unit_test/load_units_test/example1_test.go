@@ -286,8 +286,19 @@ func TestLoadExample1Project(t *testing.T) {
 					},
 				},
 			},
+			CompilerDirectives: ast.CompilerDirectives{
+				{
+					Name:     "APPTYPE",
+					Argument: "CONSOLE",
+					Args:     []string{"CONSOLE"},
+					Location: asttest.NewIdentLocation(2, 1, 19, 19),
+				},
+			},
 		},
 		Units: ast.Units{expectedUnitFoo, expectedUnitBar},
+		MissingUnits: []*parser.UnitNotFoundError{
+			{UnitName: "SysUtils", SearchedDirs: []string{"."}},
+		},
 	}
 
 	t.Run("example1.dpr", func(t *testing.T) {
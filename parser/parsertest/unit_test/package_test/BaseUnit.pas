@@ -0,0 +1,11 @@
+unit BaseUnit;
+
+interface
+
+type
+  TBase = class
+  end;
+
+implementation
+
+end.
@@ -0,0 +1,80 @@
+package package_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackage(t *testing.T) {
+	actual, err := parser.ParsePackage("MyRuntime.dpk")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "MyRuntime", actual.Ident.Name)
+	if assert.Len(t, actual.RequiresClause, 2) {
+		assert.Equal(t, "rtl", actual.RequiresClause[0].Ident.Name)
+		assert.Equal(t, "MyBase", actual.RequiresClause[1].Ident.Name)
+		// Required packages are not loaded by ParsePackage
+		assert.Nil(t, actual.RequiresClause[1].Package)
+	}
+
+	if !assert.Len(t, actual.ContainsClause, 2) {
+		return
+	}
+	assert.Equal(t, "'src\\UnitA.pas'", *actual.ContainsClause[0].Path)
+
+	if !assert.Len(t, actual.Units, 2) {
+		return
+	}
+	unitA := actual.Units.ByName("UnitA")
+	unitB := actual.Units.ByName("UnitB")
+	if !assert.NotNil(t, unitA) || !assert.NotNil(t, unitB) {
+		return
+	}
+	assert.Same(t, unitA, actual.ContainsClause.Find("UnitA").Unit)
+	assert.Same(t, unitB, actual.ContainsClause.Find("UnitB").Unit)
+	// UnitB used by UnitA is the unit contained by the package
+	assert.Same(t, unitB, unitA.InterfaceSection.UsesClause.Find("UnitB").Unit)
+
+	if assert.NotNil(t, actual.DeclMap) {
+		assert.Same(t, actual.Package, actual.DeclMap.Get("MyRuntime").Node)
+	}
+}
+
+func TestLoadPackageGraph(t *testing.T) {
+	t.Run("packages", func(t *testing.T) {
+		actual, err := parser.LoadPackageGraph("MyRuntime.dpk")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if !assert.Len(t, actual.Packages, 2) {
+			return
+		}
+		myBase := actual.Packages[0]
+		myRuntime := actual.Packages[1]
+		assert.Equal(t, "MyBase", myBase.Ident.Name)
+		assert.Equal(t, "MyRuntime", myRuntime.Ident.Name)
+		assert.Same(t, myBase, actual.Find("mybase"))
+
+		assert.Same(t, myBase.Package, myRuntime.RequiresClause.Find("MyBase").Package)
+		assert.Equal(t, []*parser.Package{myBase}, actual.Requirements("MyRuntime"))
+		assert.Equal(t, []*parser.Package{}, actual.Requirements("MyBase"))
+
+		assert.Equal(t,
+			[]*parser.PackageNotFoundError{{PackageName: "rtl", SearchedDirs: []string{"."}}},
+			actual.MissingPackages,
+		)
+		assert.Equal(t, "package rtl not found in .", actual.MissingPackages[0].Error())
+	})
+
+	t.Run("circular reference", func(t *testing.T) {
+		_, err := parser.LoadPackageGraph("circular/Cyc1.dpk")
+		if assert.Error(t, err) {
+			assert.Equal(t, "circular package reference: Cyc1 -> Cyc2 -> Cyc1", err.Error())
+		}
+	})
+}
@@ -0,0 +1,14 @@
+unit UnitA;
+
+interface
+
+uses
+  UnitB;
+
+type
+  TA = class(TB)
+  end;
+
+implementation
+
+end.
@@ -0,0 +1,11 @@
+unit UnitB;
+
+interface
+
+type
+  TB = class
+  end;
+
+implementation
+
+end.
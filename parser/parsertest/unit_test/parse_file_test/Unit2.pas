@@ -0,0 +1,13 @@
+unit Unit2;
+
+interface
+
+procedure Hello;
+
+implementation
+
+procedure Hello;
+begin
+end;
+
+end.
@@ -0,0 +1,17 @@
+unit Unit3;
+
+interface
+
+procedure Run;
+
+implementation
+
+uses
+  Unit2;
+
+procedure Run;
+begin
+  Unit2.Hello;
+end;
+
+end.
@@ -0,0 +1,64 @@
+package parsefile_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	type pattern struct {
+		path      string
+		goalType  ast.Goal
+		goalName  string
+		unitNames []string
+	}
+
+	patterns := []pattern{
+		{"Project1.dpr", &ast.Program{}, "Project1", []string{"Unit1", "Unit2"}},
+		{"MyLib.dpr", &ast.Library{}, "MyLib", []string{"Unit1", "Unit2"}},
+		{"MyPkg.dpk", &ast.Package{}, "MyPkg", []string{"Unit1", "Unit2"}},
+		{"Unit1.pas", &ast.Unit{}, "Unit1", []string{"Unit1", "Unit2"}},
+		{"Unit2.pas", &ast.Unit{}, "Unit2", []string{"Unit2"}},
+		// The unit name is different from the file name
+		{"Unit3File.pas", &ast.Unit{}, "Unit3", []string{"Unit3", "Unit2"}},
+	}
+
+	for _, ptn := range patterns {
+		t.Run(ptn.path, func(t *testing.T) {
+			actual, err := parser.ParseFile(ptn.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.IsType(t, ptn.goalType, actual.Goal)
+			assert.Equal(t, ptn.path, actual.Goal.GetPath())
+			assert.Equal(t, ptn.goalName, actual.Goal.ToDeclarations()[0].Name)
+
+			unitNames := []string{}
+			for _, u := range actual.Units {
+				unitNames = append(unitNames, u.Ident.Name)
+			}
+			assert.Equal(t, ptn.unitNames, unitNames)
+			assert.Empty(t, actual.MissingUnits)
+		})
+	}
+
+	t.Run("unit goal is one of units", func(t *testing.T) {
+		actual, err := parser.ParseFile("Unit1.pas")
+		if !assert.NoError(t, err) {
+			return
+		}
+		unit1 := actual.Goal.(*ast.Unit)
+		assert.Same(t, actual.Units.ByName("Unit1"), unit1)
+		assert.Same(t, actual.Units.ByName("Unit2"), unit1.ImplementationSection.UsesClause.Find("Unit2").Unit)
+	})
+
+	t.Run("unknown goal", func(t *testing.T) {
+		_, err := parser.ParseFile("Invalid.pas")
+		if assert.Error(t, err) {
+			assert.Equal(t, "expects PROGRAM, LIBRARY, PACKAGE or UNIT but was begin at Invalid.pas:1:1", err.Error())
+		}
+	})
+}
@@ -258,8 +258,14 @@ func TestSameIdent(t *testing.T) {
 		Program: &ast.Program{
 			Path:  "Project1.dpr",
 			Ident: asttest.NewIdent("Project1"),
+			CompilerDirectives: ast.CompilerDirectives{
+				{Name: "APPTYPE", Argument: "CONSOLE", Args: []string{"CONSOLE"}},
+			},
 		},
 		Units: ast.Units{expectUnit1, expectUnit2, expectUnit3, expectUnit4},
+		MissingUnits: []*parser.UnitNotFoundError{
+			{UnitName: "SysUtils", SearchedDirs: []string{"."}},
+		},
 	}
 	project1Unit1 := &ast.UsesClauseItem{Ident: asttest.NewIdent("Unit1"), Path: ext.StringPtr("'Unit1.pas'"), Unit: expectUnit1}
 	project1Unit2 := &ast.UsesClauseItem{Ident: asttest.NewIdent("Unit2"), Path: ext.StringPtr("'Unit2.pas'"), Unit: expectUnit2}
@@ -0,0 +1,17 @@
+unit Greeting;
+
+interface
+
+procedure Hello;
+
+implementation
+
+uses
+  Windows;
+
+procedure Hello;
+begin
+  Writeln('Hello');
+end;
+
+end.
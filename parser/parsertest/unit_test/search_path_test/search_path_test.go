@@ -0,0 +1,46 @@
+package searchpath_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchPath(t *testing.T) {
+	t.Run("with unit search path", func(t *testing.T) {
+		actualProg, err := parser.ParseProgram("search_path.dpr", parser.UnitSearchPath{"lib"})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if !assert.Len(t, actualProg.Units, 1) {
+			return
+		}
+		actualUnit := actualProg.Units[0]
+		assert.Equal(t, "Greeting", actualUnit.Ident.Name)
+		assert.Equal(t, filepath.Join("lib", "greeting.pas"), actualUnit.Path)
+
+		usesItem := actualProg.ProgramBlock.UsesClause.Find("Greeting")
+		if assert.NotNil(t, usesItem) {
+			assert.Equal(t, actualUnit, usesItem.Unit)
+		}
+
+		assert.Equal(t,
+			[]*parser.UnitNotFoundError{
+				{UnitName: "SysUtils", SearchedDirs: []string{".", "lib"}},
+				{UnitName: "Windows", SearchedDirs: []string{".", "lib"}},
+			},
+			actualProg.MissingUnits,
+		)
+		assert.Equal(t, "unit SysUtils not found in ., lib", actualProg.MissingUnits[0].Error())
+	})
+
+	t.Run("without unit search path", func(t *testing.T) {
+		_, err := parser.ParseProgram("search_path.dpr")
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "Greeting is used in uses clause but not found")
+		}
+	})
+}
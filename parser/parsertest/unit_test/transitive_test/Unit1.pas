@@ -0,0 +1,20 @@
+unit Unit1;
+
+interface
+
+uses
+  Unit2;
+
+procedure Run;
+
+implementation
+
+uses
+  Unit3;
+
+procedure Run;
+begin
+  Unit3.Proc3(Unit2.DeclInUnit2);
+end;
+
+end.
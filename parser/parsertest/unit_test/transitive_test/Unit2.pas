@@ -0,0 +1,10 @@
+unit Unit2;
+
+interface
+
+const
+  DeclInUnit2 = 2;
+
+implementation
+
+end.
@@ -0,0 +1,21 @@
+unit Unit3;
+
+interface
+
+uses
+  Unit2;
+
+procedure Proc3(Value: Integer);
+
+implementation
+
+uses
+  Unit1;
+
+procedure Proc3(Value: Integer);
+begin
+  if Value = Unit2.DeclInUnit2 then
+    Unit1.Run;
+end;
+
+end.
@@ -0,0 +1,52 @@
+package transitive_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitiveUnits(t *testing.T) {
+	actualProg, err := parser.ParseProgram("Project1.dpr")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.Len(t, actualProg.Units, 3) {
+		return
+	}
+	unit1 := actualProg.Units.ByName("Unit1")
+	unit2 := actualProg.Units.ByName("Unit2")
+	unit3 := actualProg.Units.ByName("Unit3")
+	if !assert.NotNil(t, unit1) || !assert.NotNil(t, unit2) || !assert.NotNil(t, unit3) {
+		return
+	}
+	assert.Equal(t, "Unit2.pas", unit2.Path)
+	assert.Equal(t, "Unit3.pas", unit3.Path)
+
+	// Units are shared by all uses clauses
+	assert.Same(t, unit1, actualProg.ProgramBlock.UsesClause.Find("Unit1").Unit)
+	assert.Same(t, unit2, unit1.InterfaceSection.UsesClause.Find("Unit2").Unit)
+	assert.Same(t, unit3, unit1.ImplementationSection.UsesClause.Find("Unit3").Unit)
+	assert.Same(t, unit2, unit3.InterfaceSection.UsesClause.Find("Unit2").Unit)
+	assert.Same(t, unit1, unit3.ImplementationSection.UsesClause.Find("Unit1").Unit)
+
+	// Unit2.DeclInUnit2 is resolved in Unit1 which is not used by the program directly
+	run, ok := unit1.ImplementationSection.DeclSections[0].(*ast.FunctionDecl)
+	if !assert.True(t, ok) {
+		return
+	}
+	call, ok := run.Block.Body.(*ast.CompoundStmt).StmtList[0].Body.(*ast.CallStatement)
+	if !assert.True(t, ok) {
+		return
+	}
+	if assert.NotNil(t, call.Designator.QualId.Ident.Ref) {
+		assert.Equal(t, "Proc3", call.Designator.QualId.Ident.Ref.Name)
+	}
+	arg := call.ExprList[0].SimpleExpression.Term.Factor.(*ast.DesignatorFactor)
+	if assert.NotNil(t, arg.Designator.QualId.Ident.Ref) {
+		assert.Same(t, unit2.DeclMap.Get("DeclInUnit2").Node, arg.Designator.QualId.Ident.Ref.Node)
+	}
+}
@@ -8,5 +8,8 @@ type Context interface {
 	Clone() Context
 	GetPath() string
 	StackDeclMap() func()
+	GetDefines() Defines
+	GetConstants() Constants
+	IsLossless() bool
 	astcore.DeclMap
 }
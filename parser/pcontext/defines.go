@@ -0,0 +1,20 @@
+package pcontext
+
+import "github.com/akm/tparser/preprocessor"
+
+// Defines is a set of conditional symbols used by {$IFDEF} and so on.
+type Defines = preprocessor.Defines
+
+var NewDefines = preprocessor.NewDefines
+
+// Constants are the values of identifiers such as CompilerVersion used in {$IF}.
+type Constants = preprocessor.Constants
+
+var DefaultConstants = preprocessor.DefaultConstants
+
+// IncludeSearchPath is an ordered list of directories where include files are looked up.
+type IncludeSearchPath = preprocessor.IncludeSearchPath
+
+// Lossless makes the tokens keep spaces, comments, compiler directives and
+// inactive blocks as their trivia, so that the source text can be reproduced.
+type Lossless bool
@@ -0,0 +1,95 @@
+package pcontext
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Encoding is the character encoding of source files.
+type Encoding string
+
+const (
+	ShiftJIS    Encoding = "Shift_JIS"
+	Windows1252 Encoding = "windows-1252"
+	UTF8        Encoding = "UTF-8"
+	UTF16LE     Encoding = "UTF-16LE"
+	UTF16BE     Encoding = "UTF-16BE"
+
+	// DefaultEncoding is used when no encoding is given.
+	DefaultEncoding = ShiftJIS
+)
+
+var byteOrderMarks = []struct {
+	bom      []byte
+	encoding Encoding
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+	{[]byte{0xFF, 0xFE}, UTF16LE},
+	{[]byte{0xFE, 0xFF}, UTF16BE},
+}
+
+// DetectEncoding returns the encoding indicated by the byte order mark of b
+// and the rest of b. If b doesn't start with a BOM, it returns fallback and b.
+func DetectEncoding(b []byte, fallback Encoding) (Encoding, []byte) {
+	for _, i := range byteOrderMarks {
+		if bytes.HasPrefix(b, i.bom) {
+			return i.encoding, b[len(i.bom):]
+		}
+	}
+	return fallback, b
+}
+
+// Decode converts b without BOM into a string.
+func (e Encoding) Decode(b []byte) (string, error) {
+	var enc encoding.Encoding
+	switch e {
+	case ShiftJIS:
+		enc = japanese.ShiftJIS
+	case Windows1252:
+		enc = charmap.Windows1252
+	case UTF8:
+		return string(b), nil
+	case UTF16LE:
+		enc = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case UTF16BE:
+		enc = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	default:
+		return "", errors.Errorf("unsupported encoding %q", string(e))
+	}
+	r, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return "", err
+	}
+	return string(r), nil
+}
+
+// DecodeSource converts the content of a source file into runes.
+// The encoding indicated by BOM takes precedence over e.
+func (e Encoding) DecodeSource(b []byte) ([]rune, error) {
+	enc, rest := DetectEncoding(b, e)
+	s, err := enc.Decode(rest)
+	if err != nil {
+		return nil, err
+	}
+	return []rune(s), nil
+}
+
+// FileEncodings overrides the encoding for each file path.
+type FileEncodings map[string]Encoding
+
+// Get returns the encoding for path and true if it is specified.
+func (m FileEncodings) Get(path string) (Encoding, bool) {
+	cleaned := filepath.Clean(path)
+	for k, v := range m {
+		if filepath.Clean(k) == cleaned {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,50 @@
+package pcontext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodingDecodeSource(t *testing.T) {
+	type pattern struct {
+		name     string
+		encoding Encoding
+		input    []byte
+		expected string
+	}
+
+	patterns := []pattern{
+		{"Shift_JIS", ShiftJIS, []byte{0x82, 0xa0}, "あ"},
+		{"Windows-1252", Windows1252, []byte{0x63, 0x61, 0x66, 0xe9}, "café"},
+		{"UTF-8", UTF8, []byte{0xe3, 0x81, 0x82}, "あ"},
+		{"UTF-16LE", UTF16LE, []byte{0x42, 0x30}, "あ"},
+		{"UTF-16BE", UTF16BE, []byte{0x30, 0x42}, "あ"},
+		{"UTF-8 BOM", ShiftJIS, []byte{0xef, 0xbb, 0xbf, 0xe3, 0x81, 0x82}, "あ"},
+		{"UTF-16LE BOM", ShiftJIS, []byte{0xff, 0xfe, 0x42, 0x30}, "あ"},
+		{"UTF-16BE BOM", Windows1252, []byte{0xfe, 0xff, 0x30, 0x42}, "あ"},
+	}
+
+	for _, ptn := range patterns {
+		t.Run(ptn.name, func(t *testing.T) {
+			actual, err := ptn.encoding.DecodeSource(ptn.input)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ptn.expected, string(actual))
+			}
+		})
+	}
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		_, err := Encoding("EBCDIC").DecodeSource([]byte{0x81})
+		assert.Error(t, err)
+	})
+}
+
+func TestProgramContextEncodingFor(t *testing.T) {
+	ctx := NewProgramContext("Project1.dpr", FileEncodings{"sub/Unit1.pas": UTF8})
+	assert.Equal(t, DefaultEncoding, ctx.EncodingFor("Project1.dpr"))
+	assert.Equal(t, UTF8, ctx.EncodingFor("./sub/Unit1.pas"))
+
+	ctx = NewProgramContext("Project1.dpr", Windows1252)
+	assert.Equal(t, Windows1252, ctx.EncodingFor("Unit1.pas"))
+}
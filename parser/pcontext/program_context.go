@@ -1,14 +1,27 @@
 package pcontext
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/ast/astcore"
 	"github.com/pkg/errors"
 )
 
 type ProgramContext struct {
-	Path  string
-	Units ast.Units
+	Path           string
+	Units          ast.Units
+	UnitSearchPath UnitSearchPath
+	MissingUnits   []*UnitNotFoundError
+	Encoding       Encoding
+	FileEncodings  FileEncodings
+	Defines        Defines
+	// Constants are DefaultConstants overwritten by the ones given to NewProgramContext.
+	Constants Constants
+	// IncludeSearchPath is used for {$I file} which is not found in the directory of the including file.
+	IncludeSearchPath IncludeSearchPath
+	Lossless          Lossless
 	astcore.DeclMap
 }
 
@@ -17,6 +30,13 @@ var _ Context = (*ProgramContext)(nil)
 func NewProgramContext(args ...interface{}) *ProgramContext {
 	var path string
 	var units ast.Units
+	var searchPath UnitSearchPath
+	var encoding Encoding
+	var fileEncodings FileEncodings
+	var defines Defines
+	var constants Constants
+	var includeSearchPath IncludeSearchPath
+	var lossless Lossless
 	var declarationMap astcore.DeclMap
 	for _, arg := range args {
 		switch v := arg.(type) {
@@ -24,6 +44,20 @@ func NewProgramContext(args ...interface{}) *ProgramContext {
 			path = v
 		case ast.Units:
 			units = v
+		case UnitSearchPath:
+			searchPath = v
+		case Encoding:
+			encoding = v
+		case FileEncodings:
+			fileEncodings = v
+		case Defines:
+			defines = v
+		case Constants:
+			constants = v
+		case IncludeSearchPath:
+			includeSearchPath = v
+		case Lossless:
+			lossless = v
 		case astcore.DeclMap:
 			declarationMap = v
 		default:
@@ -36,18 +70,36 @@ func NewProgramContext(args ...interface{}) *ProgramContext {
 	if declarationMap == nil {
 		declarationMap = astcore.NewChainedDeclMap(ast.EmbeddedTypeDeclMap)
 	}
+	if encoding == "" {
+		encoding = DefaultEncoding
+	}
 	return &ProgramContext{
-		Path:    path,
-		Units:   units,
-		DeclMap: declarationMap,
+		Path:              path,
+		Units:             units,
+		UnitSearchPath:    searchPath,
+		Encoding:          encoding,
+		FileEncodings:     fileEncodings,
+		Defines:           defines,
+		Constants:         DefaultConstants().Merge(constants),
+		IncludeSearchPath: includeSearchPath,
+		Lossless:          lossless,
+		DeclMap:           declarationMap,
 	}
 }
 
 func (c *ProgramContext) Clone() Context {
 	return &ProgramContext{
-		Path:    c.Path,
-		Units:   c.Units,
-		DeclMap: c.DeclMap,
+		Path:              c.Path,
+		Units:             c.Units,
+		UnitSearchPath:    c.UnitSearchPath,
+		MissingUnits:      c.MissingUnits,
+		Encoding:          c.Encoding,
+		FileEncodings:     c.FileEncodings,
+		Defines:           c.Defines,
+		Constants:         c.Constants,
+		IncludeSearchPath: c.IncludeSearchPath,
+		Lossless:          c.Lossless,
+		DeclMap:           c.DeclMap,
 	}
 }
 
@@ -55,10 +107,85 @@ func (c *ProgramContext) GetPath() string {
 	return c.Path
 }
 
+// GetDefines returns the conditional symbols given to the program.
+func (c *ProgramContext) GetDefines() Defines {
+	return c.Defines
+}
+
+// GetConstants returns the constants for {$IF} given to the program.
+func (c *ProgramContext) GetConstants() Constants {
+	return c.Constants
+}
+
+// IsLossless returns true if the tokens keep their trivia.
+func (c *ProgramContext) IsLossless() bool {
+	return bool(c.Lossless)
+}
+
 func (c *ProgramContext) AddUnit(unit *ast.Unit) {
 	c.Units = append(c.Units, unit)
 }
 
+// EncodingFor returns the encoding for the file at path.
+// FileEncodings takes precedence over Encoding.
+func (c *ProgramContext) EncodingFor(path string) Encoding {
+	if enc, ok := c.FileEncodings.Get(path); ok {
+		return enc
+	}
+	return c.Encoding
+}
+
+// EffectiveUnitSearchPath returns the directory of the program followed by
+// the directories of UnitSearchPath. Relative directories are resolved from
+// the directory of the program.
+func (c *ProgramContext) EffectiveUnitSearchPath() UnitSearchPath {
+	baseDir := filepath.Dir(c.Path)
+	r := UnitSearchPath{baseDir}
+	for _, dir := range c.UnitSearchPath {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		r = append(r, dir)
+	}
+	return r
+}
+
+// EffectiveIncludeSearchPath returns the directories of IncludeSearchPath.
+// Relative directories are resolved from the directory of the program.
+func (c *ProgramContext) EffectiveIncludeSearchPath() IncludeSearchPath {
+	baseDir := filepath.Dir(c.Path)
+	r := IncludeSearchPath{}
+	for _, dir := range c.IncludeSearchPath {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		r = append(r, dir)
+	}
+	return r
+}
+
+// FindUnitPath returns the path of the unit file found in EffectiveUnitSearchPath.
+// When the unit is not found, the returned *UnitNotFoundError is also recorded in MissingUnits.
+func (c *ProgramContext) FindUnitPath(unitName string) (string, error) {
+	path, err := c.EffectiveUnitSearchPath().Find(unitName)
+	if err != nil {
+		if notFound, ok := err.(*UnitNotFoundError); ok {
+			c.addMissingUnit(notFound)
+		}
+		return "", err
+	}
+	return path, nil
+}
+
+func (c *ProgramContext) addMissingUnit(err *UnitNotFoundError) {
+	for _, i := range c.MissingUnits {
+		if strings.EqualFold(i.UnitName, err.UnitName) {
+			return
+		}
+	}
+	c.MissingUnits = append(c.MissingUnits, err)
+}
+
 func (c *ProgramContext) StackDeclMap() func() {
 	var backup astcore.DeclMap
 	c.DeclMap, backup = astcore.NewChainedDeclMap(c.DeclMap), c.DeclMap
@@ -47,6 +47,18 @@ func (c *StackableContext) GetPath() string {
 	return c.parent.GetPath()
 }
 
+func (c *StackableContext) GetDefines() Defines {
+	return c.parent.GetDefines()
+}
+
+func (c *StackableContext) GetConstants() Constants {
+	return c.parent.GetConstants()
+}
+
+func (c *StackableContext) IsLossless() bool {
+	return c.parent.IsLossless()
+}
+
 func (c *StackableContext) StackDeclMap() func() {
 	var backup astcore.DeclMap
 	c.declMap, backup = astcore.NewChainedDeclMap(c.declMap), c.declMap
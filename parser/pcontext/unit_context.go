@@ -56,11 +56,17 @@ func (c *UnitContext) ImportUnitDecls(usesClause ast.UsesClause) {
 	c.DeclMap = astcore.NewCompositeDeclMap(maps...)
 }
 
+// AssignUnits sets the units loaded by the program to the items of usesClause.
+// Units which are not loaded and not found in the unit search path are recorded
+// in MissingUnits of the program context.
 func (c *UnitContext) AssignUnits(usesClause ast.UsesClause) {
 	parentUnits := c.Parent.Units
 	for _, unitItem := range usesClause {
 		if u := parentUnits.ByName(unitItem.Ident.Name); u != nil {
 			unitItem.Unit = u
+		} else if unitItem.EffectivePath() == "" {
+			// The error is recorded in MissingUnits of the program context.
+			c.Parent.FindUnitPath(unitItem.Ident.Name)
 		}
 	}
 }
@@ -69,6 +75,30 @@ func (c *UnitContext) GetPath() string {
 	return c.Path
 }
 
+// GetDefines returns the conditional symbols given to the program.
+func (c *UnitContext) GetDefines() Defines {
+	if c.Parent == nil {
+		return nil
+	}
+	return c.Parent.GetDefines()
+}
+
+// GetConstants returns the constants for {$IF} given to the program.
+func (c *UnitContext) GetConstants() Constants {
+	if c.Parent == nil {
+		return nil
+	}
+	return c.Parent.GetConstants()
+}
+
+// IsLossless returns true if the tokens keep their trivia.
+func (c *UnitContext) IsLossless() bool {
+	if c.Parent == nil {
+		return false
+	}
+	return c.Parent.IsLossless()
+}
+
 func (c *UnitContext) StackDeclMap() func() {
 	var backup astcore.DeclMap
 	c.DeclMap, backup = astcore.NewChainedDeclMap(c.DeclMap), c.DeclMap
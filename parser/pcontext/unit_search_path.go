@@ -0,0 +1,59 @@
+package pcontext
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UnitSearchPath is an ordered list of directories where units which are
+// used without `IN` path are looked up.
+type UnitSearchPath []string
+
+// Find returns the path of `<unitName>.pas` in the first directory which contains it.
+// File names are compared case-insensitively like Delphi on Windows does.
+func (s UnitSearchPath) Find(unitName string) (string, error) {
+	path, err := s.FindFile(unitName + ".pas")
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", &UnitNotFoundError{UnitName: unitName, SearchedDirs: s}
+	}
+	return path, nil
+}
+
+// FindFile returns the path of fileName in the first directory which contains it.
+// It returns "" if no directory contains it.
+func (s UnitSearchPath) FindFile(fileName string) (string, error) {
+	lowerName := strings.ToLower(fileName)
+	for _, dir := range s {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if strings.ToLower(entry.Name()) == lowerName {
+				return filepath.Join(dir, entry.Name()), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// UnitNotFoundError is returned when a unit is not found in any directory of UnitSearchPath.
+type UnitNotFoundError struct {
+	UnitName     string
+	SearchedDirs []string
+}
+
+func (e *UnitNotFoundError) Error() string {
+	return fmt.Sprintf("unit %s not found in %s", e.UnitName, strings.Join(e.SearchedDirs, ", "))
+}
@@ -1,64 +1,61 @@
 package parser
 
 import (
-	"io/ioutil"
-	"os"
+	"strings"
 
 	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
 	"github.com/pkg/errors"
-	"golang.org/x/text/encoding/japanese"
-	"golang.org/x/text/transform"
 )
 
 type Program struct {
 	*ast.Program
-	Units ast.Units
+	Units        ast.Units
+	MissingUnits []*UnitNotFoundError
 }
 
-func ParseProgram(path string) (*Program, error) {
-	fp, err := os.Open(path)
-	if err != nil {
-		panic(err)
-	}
-	defer fp.Close()
-
-	decoder := japanese.ShiftJIS.NewDecoder()
-	str, err := ioutil.ReadAll(transform.NewReader(fp, decoder))
+// ParseProgram parses the program file and the units used by it.
+// args are passed to NewProgramContext, so UnitSearchPath can be given
+// to find units which are used without `IN` path, and Encoding and
+// FileEncodings can be given to decode source files, and Defines and
+// IncludeSearchPath can be given for compiler directives.
+func ParseProgram(path string, args ...interface{}) (*Program, error) {
+	ctx := NewProgramContext(append([]interface{}{path}, args...)...)
+	runes, err := readSourceFile(path, ctx.EncodingFor(path))
 	if err != nil {
 		return nil, err
 	}
 
-	runes := []rune(string(str))
-
-	// absPath, err := filepath.Abs(path)
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	ctx := NewProgramContext(path)
 	p := NewProgramParser(ctx)
 	p.SetText(&runes)
+	p.setupIncludes(ctx)
 	p.NextToken()
 	res, err := p.ParseProgram()
 	if err != nil {
 		return nil, err
 	}
 	return &Program{
-		Program: res,
-		Units:   ctx.Units,
+		Program:      res,
+		Units:        ctx.Units,
+		MissingUnits: ctx.MissingUnits,
 	}, nil
 }
 
 type ProgramParser struct {
 	*Parser
-	Program *ast.Program
-	context *ProgramContext
+	Program     *ast.Program
+	context     *ProgramContext
+	unitParsers map[string]*UnitParser
+	goal        ast.Goal // Program, Library or Package which uses the units
 }
 
 func NewProgramParser(ctx *ProgramContext) *ProgramParser {
-	return &ProgramParser{Parser: NewParser(ctx), context: ctx}
+	return &ProgramParser{
+		Parser:      NewParser(ctx),
+		context:     ctx,
+		unitParsers: map[string]*UnitParser{},
+	}
 }
 
 func (p *ProgramParser) ParseProgram() (*ast.Program, error) {
@@ -74,6 +71,7 @@ func (p *ProgramParser) ParseProgram() (*ast.Program, error) {
 		Ident: p.NewIdent(ident),
 	}
 	p.Program = res
+	p.goal = res
 	// p.context.DeclMap.Set(res)
 	if _, err := p.Next(token.Symbol(';')); err != nil {
 		return nil, err
@@ -87,6 +85,7 @@ func (p *ProgramParser) ParseProgram() (*ast.Program, error) {
 	if _, err := p.Current(token.Symbol('.')); err != nil {
 		return nil, err
 	}
+	res.CompilerDirectives = p.CompilerDirectives()
 	return res, nil
 }
 
@@ -112,37 +111,60 @@ func (p *ProgramParser) ParseProgramBlock() (*ast.ProgramBlock, error) {
 	return res, nil
 }
 
+// LoadUnits loads the units used by the goal and the units used by them
+// recursively. Each unit is parsed only once even if it is used by several units.
 func (p *ProgramParser) LoadUnits(uses ast.UsesClause) error {
-	parsers := UnitParsers{}
-	for _, unitRef := range uses {
-		path := unitRef.EffectivePath()
-		if path != "" {
-			parsers = append(parsers, NewUnitParser(NewUnitContext(p.context, path)))
+	parsers := UnitParsers{} // Units used by the program directly
+	for _, usesItem := range uses {
+		loader, _, err := p.loadUnit(usesItem)
+		if err != nil {
+			return err
+		}
+		if loader != nil {
+			parsers = append(parsers, loader)
 		}
 	}
 
-	for _, loader := range parsers {
-		if err := loader.LoadFile(); err != nil {
+	// Units used by implementation sections are found after parsing their interface sections.
+	// So units are processed in batches until no more unit is found.
+	processed := UnitParsers{}
+	pending := parsers
+	for len(pending) > 0 {
+		batch, err := p.loadIntfUses(pending)
+		if err != nil {
 			return err
 		}
-		if err := loader.ProcessIdentAndIntfUses(); err != nil {
+
+		sortedLoaders, err := batch.Sort()
+		if err != nil {
 			return err
 		}
-		p.context.AddUnit(loader.Unit)
-	}
 
-	sortedLoaders, err := parsers.Sort()
-	if err != nil {
-		return err
-	}
+		for _, loader := range sortedLoaders {
+			if err := loader.ProcessIntfBody(); err != nil {
+				return err
+			}
+		}
 
-	for _, loader := range sortedLoaders {
-		if err := loader.ProcessIntfBody(); err != nil {
-			return err
+		pending = UnitParsers{}
+		for _, loader := range sortedLoaders {
+			if err := loader.ProcessImplUses(); err != nil {
+				return err
+			}
+			for _, usesItem := range loader.Unit.ImplementationSection.UsesClause {
+				implLoader, loaded, err := p.loadUnit(usesItem)
+				if err != nil {
+					return err
+				}
+				if loaded {
+					pending = append(pending, implLoader)
+				}
+			}
 		}
+		processed = append(processed, sortedLoaders...)
 	}
 
-	for _, loader := range sortedLoaders {
+	for _, loader := range processed {
 		if err := loader.ProcessImplAndInit(); err != nil {
 			return err
 		}
@@ -165,8 +187,72 @@ func (p *ProgramParser) LoadUnits(uses ast.UsesClause) error {
 	maps = append(maps, parsers.DeclMaps().Reverse()...)
 	maps = append(maps, p.context.DeclMap)
 	p.context.DeclMap = astcore.NewCompositeDeclMap(maps...)
-	p.Program.DeclMap = localMap
-	localMap.Set(p.Program)
+	switch goal := p.goal.(type) {
+	case *ast.Program:
+		goal.DeclMap = localMap
+	case *ast.Library:
+		goal.DeclMap = localMap
+	case *ast.Package:
+		goal.DeclMap = localMap
+	}
+	if p.goal != nil {
+		localMap.Set(p.goal)
+	}
 
 	return nil
 }
+
+// loadIntfUses returns the given parsers and the parsers of units which are
+// used by interface sections of them recursively.
+func (p *ProgramParser) loadIntfUses(parsers UnitParsers) (UnitParsers, error) {
+	r := UnitParsers{}
+	queue := append(UnitParsers{}, parsers...)
+	for len(queue) > 0 {
+		loader := queue[0]
+		queue = queue[1:]
+		r = append(r, loader)
+		for _, usesItem := range loader.Unit.InterfaceSection.UsesClause {
+			intfLoader, loaded, err := p.loadUnit(usesItem)
+			if err != nil {
+				return nil, err
+			}
+			if loaded {
+				queue = append(queue, intfLoader)
+			}
+		}
+	}
+	return r, nil
+}
+
+// loadUnit returns the UnitParser for usesItem and true if the unit is loaded newly.
+// It returns nil if the unit file is not found.
+func (p *ProgramParser) loadUnit(usesItem *ast.UsesClauseItem) (*UnitParser, bool, error) {
+	key := strings.ToLower(usesItem.Ident.Name)
+	if loader, ok := p.unitParsers[key]; ok {
+		return loader, false, nil
+	}
+
+	path := usesItem.EffectivePath()
+	if path == "" {
+		found, err := p.context.FindUnitPath(usesItem.Ident.Name)
+		if err != nil {
+			if _, ok := err.(*UnitNotFoundError); ok {
+				p.Logf("%s", err.Error())
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		path = found
+	}
+
+	loader := NewUnitParser(NewUnitContext(p.context, path))
+	if err := loader.LoadFile(); err != nil {
+		return nil, false, err
+	}
+	if err := loader.ProcessIdentAndIntfUses(); err != nil {
+		return nil, false, err
+	}
+	p.context.AddUnit(loader.Unit)
+	p.unitParsers[key] = loader
+	return loader, true, nil
+}
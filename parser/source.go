@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// readSourceFile reads the file at path and decodes it with enc.
+// The encoding indicated by BOM takes precedence over enc.
+func readSourceFile(path string, enc Encoding) ([]rune, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open file: %q", path)
+	}
+	r, err := enc.DecodeSource(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode file: %q", path)
+	}
+	return r, nil
+}
+
+// setupIncludes enables {$I file} in the text given by SetText.
+// Include files are decoded with the encoding for them in prog.
+func (p *Parser) setupIncludes(prog *ProgramContext) {
+	p.tokenizer.Path = p.context.GetPath()
+	p.tokenizer.IncludeSearchPath = prog.EffectiveIncludeSearchPath()
+	p.tokenizer.ReadFile = func(path string) ([]rune, error) {
+		return readSourceFile(path, prog.EncodingFor(path))
+	}
+}
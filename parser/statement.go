@@ -18,6 +18,9 @@ func (p *Parser) ParseCompoundStmt(required bool) (*ast.CompoundStmt, error) {
 	}
 	p.NextToken()
 
+	// Inline variables are visible only in the compound statement.
+	defer p.context.StackDeclMap()()
+
 	terminator := token.ReservedWord.HasKeyword("END")
 	stmtList, err := p.ParseStmtList(terminator)
 	if err != nil {
@@ -31,6 +34,10 @@ func (p *Parser) ParseCompoundStmt(required bool) (*ast.CompoundStmt, error) {
 }
 
 func (p *Parser) ParseStmtList(terminator token.Predicator) (ast.StmtList, error) {
+	if p.CurrentToken().Is(terminator) {
+		// Empty statement list such as `begin end`
+		return nil, nil
+	}
 	res := ast.StmtList{}
 	for {
 		statement, err := p.ParseStatement()
@@ -54,7 +61,7 @@ func (p *Parser) ParseStmtList(terminator token.Predicator) (ast.StmtList, error
 }
 
 func (p *Parser) ParseStatement() (*ast.Statement, error) {
-	res := &ast.Statement{}
+	res := &ast.Statement{CompilerDirectives: p.leadingCompilerDirectives(p.CurrentToken())}
 	labelId := p.CurrentToken()
 	labelDecl := p.context.Get(labelId.Value())
 	if labelDecl != nil {
@@ -154,6 +161,13 @@ func (p *Parser) ParseStatement() (*ast.Statement, error) {
 				res.Body = stmt
 				return res, nil
 			}
+		case "VAR":
+			if stmt, err := p.ParseInlineVarStatement(); err != nil {
+				return nil, err
+			} else {
+				res.Body = stmt
+				return res, nil
+			}
 		}
 	}
 
@@ -222,3 +236,42 @@ func (p *Parser) ParseGotoStatement() (*ast.GotoStatement, error) {
 		Ref:     d,
 	}, nil
 }
+
+func (p *Parser) ParseInlineVarStatement() (*ast.InlineVarStatement, error) {
+	if _, err := p.Current(token.ReservedWord.HasKeyword("VAR")); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	identList, err := p.ParseIdentListBy(token.Some(token.Symbol(':'), token.Symbol(':', '=')))
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.InlineVarStatement{IdentList: *identList}
+	if p.CurrentToken().Is(token.Symbol(':')) {
+		p.NextToken()
+		typ, err := p.ParseType()
+		if err != nil {
+			return nil, err
+		}
+		res.Type = typ
+	}
+	if p.CurrentToken().Is(token.Symbol(':', '=')) {
+		p.NextToken()
+		expr, err := p.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+		res.Expression = expr
+	}
+	if res.Type == nil {
+		if res.Expression == nil {
+			return nil, p.TokenErrorf("expected type or value of inline variable, but got %s", p.CurrentToken())
+		}
+		res.Inferred = true
+	}
+	// The variable can't be referred in its initial value.
+	if err := p.context.Set(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -1,27 +1,417 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/akm/tparser/ast"
-	"github.com/akm/tparser/log"
+	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
 )
 
+var (
+	asmRegisters = toUpperSet(
+		"AL", "AH", "AX", "EAX", "RAX", "BL", "BH", "BX", "EBX", "RBX",
+		"CL", "CH", "CX", "ECX", "RCX", "DL", "DH", "DX", "EDX", "RDX",
+		"SIL", "SI", "ESI", "RSI", "DIL", "DI", "EDI", "RDI",
+		"BPL", "BP", "EBP", "RBP", "SPL", "SP", "ESP", "RSP",
+		"R8", "R8D", "R8W", "R8B", "R9", "R9D", "R9W", "R9B",
+		"R10", "R10D", "R10W", "R10B", "R11", "R11D", "R11W", "R11B",
+		"R12", "R12D", "R12W", "R12B", "R13", "R13D", "R13W", "R13B",
+		"R14", "R14D", "R14W", "R14B", "R15", "R15D", "R15W", "R15B",
+		"CS", "DS", "ES", "FS", "GS", "SS",
+		"ST", "MM0", "MM1", "MM2", "MM3", "MM4", "MM5", "MM6", "MM7",
+		"XMM0", "XMM1", "XMM2", "XMM3", "XMM4", "XMM5", "XMM6", "XMM7",
+		"XMM8", "XMM9", "XMM10", "XMM11", "XMM12", "XMM13", "XMM14", "XMM15",
+		"CR0", "CR2", "CR3", "CR4", "DR0", "DR1", "DR2", "DR3", "DR6", "DR7",
+	)
+	asmSegmentRegisters = toUpperSet("CS", "DS", "ES", "FS", "GS", "SS")
+	asmPrefixes         = toUpperSet("LOCK", "REP", "REPE", "REPZ", "REPNE", "REPNZ")
+	asmDirectives       = toUpperSet("DB", "DW", "DD", "DQ", "ALIGN")
+	asmDotDirectives    = toUpperSet("NOFRAME", "PARAMS", "PUSHNV", "SAVENV")
+	asmPtrSizes         = toUpperSet("BYTE", "WORD", "DWORD", "QWORD", "TBYTE", "DQWORD")
+	asmUnaryOps         = toUpperSet("NOT", "OFFSET", "VMTOFFSET", "DMTINDEX", "TYPE", "HIGH", "LOW", "SHORT")
+	asmAddOps           = toUpperSet("+", "-", "OR", "XOR")
+	asmMulOps           = toUpperSet("*", "/", "AND", "MOD", "SHL", "SHR")
+)
+
+func toUpperSet(words ...string) map[string]bool {
+	r := make(map[string]bool, len(words))
+	for _, w := range words {
+		r[strings.ToUpper(w)] = true
+	}
+	return r
+}
+
+// ParseAssemblerStatement parses an asm block with the tokenizer in asm mode.
+// Identifiers in operands are resolved through the context, and
+// labels starting with '@' are resolved in the asm block.
 func (p *Parser) ParseAssemblerStatement() (*ast.AssemblerStatement, error) {
 	if _, err := p.Current(token.ReservedWord.HasKeyword("ASM")); err != nil {
 		return nil, err
 	}
+	p.tokenizer.SetAsmMode(true)
+	// Rollbacks in the block replace the tokenizer, so it is looked up when returning.
+	defer func() { p.tokenizer.SetAsmMode(false) }()
+	p.NextToken()
+
+	res := &ast.AssemblerStatement{}
+	a := &asmParser{Parser: p, labels: astcore.NewDeclMap()}
+	for !p.CurrentToken().Is(token.ReservedWord.HasKeyword("END")) {
+		if p.CurrentToken().Is(token.EOF) {
+			return nil, p.TokenErrorf("expects END for ASM but was %s", p.CurrentToken())
+		}
+		stmts, err := a.parseStatements()
+		if err != nil {
+			return nil, err
+		}
+		res.Statements = append(res.Statements, stmts...)
+	}
+	p.tokenizer.SetAsmMode(false)
 	p.NextToken()
 
-	log.Printf("ParseAssemblerStatement start")
+	for _, ref := range a.labelRefs {
+		ref.Ref = a.labels.Get(ref.Name)
+	}
+	return res, nil
+}
+
+type asmParser struct {
+	*Parser
+	line      int // the line of the current AsmStatement
+	labels    astcore.DeclMap
+	labelRefs []*ast.AsmIdent
+}
+
+// endOfStatement returns true if the current token is not a part of the current statement.
+func (p *asmParser) endOfStatement() bool {
+	t := p.CurrentToken()
+	return t.Type == token.EOF || t.Start.Line != p.line ||
+		t.Is(token.Symbol(';')) || t.Is(token.ReservedWord.HasKeyword("END"))
+}
+
+// parseStatements parses labels and an instruction or a directive in a line or before ';'.
+func (p *asmParser) parseStatements() (ast.AsmStatements, error) {
+	t0 := p.CurrentToken()
+	p.line = t0.Start.Line
+	res := ast.AsmStatements{}
+
+	if t0.Is(token.Symbol(';')) {
+		p.NextToken()
+		return res, nil
+	}
+
+	for p.CurrentToken().Is(token.Identifier) {
+		t := p.CurrentToken()
+		rollback := p.RollbackPoint()
+		if !p.NextToken().Is(token.Symbol(':')) || asmRegisters[strings.ToUpper(t.RawString())] {
+			rollback()
+			break
+		}
+		label := &ast.AsmLabel{Ident: p.NewIdent(t)}
+		if strings.HasPrefix(label.Name, "@") {
+			if err := p.labels.Set(label); err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, label)
+		p.NextToken()
+	}
+	if p.endOfStatement() {
+		return res, nil
+	}
 
+	t1 := p.CurrentToken()
+	if t1.Is(token.Symbol('.')) {
+		t2 := p.NextToken()
+		if !asmDotDirectives[strings.ToUpper(t2.RawString())] {
+			return nil, p.TokenErrorf("unknown asm directive .%s", t2)
+		}
+		ident := p.NewIdent(t2)
+		ident.Name = "." + ident.Name
+		p.NextToken()
+		operands, err := p.parseOperands()
+		if err != nil {
+			return nil, err
+		}
+		return append(res, &ast.AsmDirective{Ident: ident, Operands: operands}), nil
+	}
+
+	if _, err := p.Current(token.Identifier); err != nil {
+		return nil, err
+	}
+	if asmDirectives[strings.ToUpper(t1.RawString())] {
+		p.NextToken()
+		operands, err := p.parseOperands()
+		if err != nil {
+			return nil, err
+		}
+		return append(res, &ast.AsmDirective{Ident: p.NewIdent(t1), Operands: operands}), nil
+	}
+
+	instruction := &ast.AsmInstruction{Opcode: p.NewIdent(t1)}
+	p.NextToken()
+	if asmPrefixes[strings.ToUpper(t1.RawString())] && !p.endOfStatement() && p.CurrentToken().Is(token.Identifier) {
+		instruction.Prefix = instruction.Opcode
+		instruction.Opcode = p.NewIdent(p.CurrentToken())
+		p.NextToken()
+	}
+	operands, err := p.parseOperands()
+	if err != nil {
+		return nil, err
+	}
+	instruction.Operands = operands
+	return append(res, instruction), nil
+}
+
+func (p *asmParser) parseOperands() (ast.AsmOperands, error) {
+	if p.endOfStatement() {
+		return nil, nil
+	}
+	res := ast.AsmOperands{}
 	for {
-		if p.NextToken().Is(token.ReservedWord.HasKeyword("END")) {
-			p.NextToken()
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, operand)
+		if p.endOfStatement() || !p.CurrentToken().Is(token.Symbol(',')) {
 			break
 		}
+		p.NextToken()
+	}
+	if !p.endOfStatement() {
+		return nil, p.TokenErrorf("unexpected token %s in asm statement", p.CurrentToken())
+	}
+	return res, nil
+}
+
+func (p *asmParser) currentOp(ops map[string]bool) string {
+	if p.endOfStatement() {
+		return ""
+	}
+	t := p.CurrentToken()
+	if t.Type != token.Identifier && t.Type != token.SpecialSymbol {
+		return ""
+	}
+	op := strings.ToUpper(t.RawString())
+	if ops[op] {
+		return op
+	}
+	return ""
+}
+
+func (p *asmParser) parseExpr() (ast.AsmOperand, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.currentOp(asmAddOps); op != ""; op = p.currentOp(asmAddOps) {
+		p.NextToken()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.AsmBinaryExpr{Left: left, Op: op, Right: right}
+	}
+	return left, nil
+}
+
+func (p *asmParser) parseTerm() (ast.AsmOperand, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.currentOp(asmMulOps); op != ""; op = p.currentOp(asmMulOps) {
+		p.NextToken()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &ast.AsmBinaryExpr{Left: left, Op: op, Right: right}
+	}
+	return left, nil
+}
+
+func (p *asmParser) parseUnary() (ast.AsmOperand, error) {
+	if p.endOfStatement() {
+		return nil, p.TokenErrorf("expects asm operand but was %s", p.CurrentToken())
+	}
+	t := p.CurrentToken()
+	word := strings.ToUpper(t.RawString())
+	if t.Is(token.Symbol('+')) || t.Is(token.Symbol('-')) || (t.Type == token.Identifier && asmUnaryOps[word]) {
+		p.NextToken()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.AsmUnaryExpr{Op: word, Operand: operand}, nil
+	}
+	if t.Type == token.Identifier && asmPtrSizes[word] {
+		rollback := p.RollbackPoint()
+		if next := p.NextToken(); !p.endOfStatement() && strings.ToUpper(next.RawString()) == "PTR" {
+			p.NextToken()
+			operand, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			return &ast.AsmPtr{Size: word, Operand: operand}, nil
+		}
+		rollback()
 	}
+	return p.parsePostfix()
+}
 
-	log.Printf("ParseAssemblerStatement done")
+func (p *asmParser) parsePostfix() (ast.AsmOperand, error) {
+	res, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for !p.endOfStatement() {
+		switch {
+		case p.CurrentToken().Is(token.Symbol('.')):
+			t, err := p.Next(token.Identifier)
+			if err != nil {
+				return nil, err
+			}
+			decl := p.asmMemberDecl(res, t.RawString())
+			if _, ok := res.(*ast.AsmMemoryRef); ok && decl == nil {
+				// A type name follows the memory reference such as [EAX].TPoint.X
+				decl = p.context.Get(t.RawString())
+			}
+			member := &ast.AsmIdent{IdentRef: ast.NewIdentRef(p.NewIdent(t), decl)}
+			res = &ast.AsmBinaryExpr{Left: res, Op: ".", Right: member}
+			p.NextToken()
+		case p.CurrentToken().Is(token.Symbol('[')):
+			memRef, err := p.parseMemoryRef(nil)
+			if err != nil {
+				return nil, err
+			}
+			res = &ast.AsmBinaryExpr{Left: res, Op: "+", Right: memRef}
+		default:
+			return res, nil
+		}
+	}
+	return res, nil
+}
 
-	return &ast.AssemblerStatement{}, nil
+func (p *asmParser) parsePrimary() (ast.AsmOperand, error) {
+	t := p.CurrentToken()
+	switch t.Type {
+	case token.NumeralInt, token.NumeralReal:
+		p.NextToken()
+		return &ast.AsmNumber{Value: t.RawString()}, nil
+	case token.CharacterString:
+		p.NextToken()
+		return &ast.AsmString{Value: t.RawString()}, nil
+	case token.SpecialSymbol:
+		switch {
+		case t.Is(token.Symbol('(')):
+			p.NextToken()
+			res, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.Current(token.Symbol(')')); err != nil {
+				return nil, err
+			}
+			p.NextToken()
+			return res, nil
+		case t.Is(token.Symbol('[')):
+			return p.parseMemoryRef(nil)
+		}
+	case token.Identifier:
+		if asmRegisters[strings.ToUpper(t.RawString())] {
+			return p.parseRegister()
+		}
+		ident := p.NewIdent(t)
+		p.NextToken()
+		res := &ast.AsmIdent{IdentRef: ast.NewIdentRef(ident, nil)}
+		if strings.HasPrefix(ident.Name, "@") {
+			p.labelRefs = append(p.labelRefs, res)
+		} else {
+			res.Ref = p.context.Get(ident.Name)
+		}
+		return res, nil
+	}
+	return nil, p.TokenErrorf("unexpected token %s for asm operand", t)
+}
+
+func (p *asmParser) parseRegister() (ast.AsmOperand, error) {
+	t := p.CurrentToken()
+	res := &ast.AsmRegister{Ident: p.NewIdent(t)}
+	p.NextToken()
+	name := strings.ToUpper(t.RawString())
+	if name == "ST" && !p.endOfStatement() && p.CurrentToken().Is(token.Symbol('(')) {
+		index, err := p.Next(token.NumeralInt)
+		if err != nil {
+			return nil, err
+		}
+		res.Index = &ast.AsmNumber{Value: index.RawString()}
+		if _, err := p.Next(token.Symbol(')')); err != nil {
+			return nil, err
+		}
+		p.NextToken()
+	}
+	if asmSegmentRegisters[name] && !p.endOfStatement() && p.CurrentToken().Is(token.Symbol(':')) {
+		p.NextToken()
+		if p.CurrentToken().Is(token.Symbol('[')) {
+			return p.parseMemoryRef(res)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.AsmMemoryRef{Segment: res, Operand: operand}, nil
+	}
+	return res, nil
+}
+
+func (p *asmParser) parseMemoryRef(segment *ast.AsmRegister) (*ast.AsmMemoryRef, error) {
+	if _, err := p.Current(token.Symbol('[')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	operand, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Current(token.Symbol(']')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return &ast.AsmMemoryRef{Segment: segment, Operand: operand}, nil
+}
+
+// asmMemberDecl returns the declaration of the member of the type which operand refers,
+// such as VirtualMethod of `TExample.VirtualMethod` or X of `Point.X`.
+func (p *asmParser) asmMemberDecl(operand ast.AsmOperand, name string) *astcore.Decl {
+	if expr, ok := operand.(*ast.AsmBinaryExpr); ok && expr.Op == "." {
+		operand = expr.Right
+	}
+	ident, ok := operand.(*ast.AsmIdent)
+	if !ok || ident.Ref == nil {
+		return nil
+	}
+	var typ ast.Type
+	switch v := ident.Ref.Node.(type) {
+	case *ast.TypeDecl:
+		typ = v.Type
+	case *ast.VarDecl:
+		typ = v.Type
+	case *ast.FieldDecl:
+		typ = v.Type
+	case *ast.ClassField:
+		typ = v.Type
+	default:
+		return nil
+	}
+	if classType := classMembersTypeOf(actualType(typ)); classType != nil {
+		return classType.FindMemberDecl(name, p.memberAccess(nil))
+	}
+	if recType, ok := actualType(typ).(*ast.RecType); ok {
+		if fieldDecl := findRecordField(recType, name); fieldDecl != nil {
+			return fieldDecl.ToDeclarations().Find(name)
+		}
+	}
+	return nil
 }
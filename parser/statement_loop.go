@@ -52,15 +52,51 @@ func (p *Parser) ParseWhileStmt() (*ast.WhileStmt, error) {
 	}, nil
 }
 
-func (p *Parser) ParseForStmt() (*ast.ForStmt, error) {
+// ParseForStmt parses ForStmt or ForInStmt.
+func (p *Parser) ParseForStmt() (ast.LoopStmt, error) {
 	if _, err := p.Current(token.ReservedWord.HasKeyword("FOR")); err != nil {
 		return nil, err
 	}
 	p.NextToken()
-	qualId, err := p.ParseQualId()
-	if err != nil {
-		return nil, err
+
+	var forVar *ast.InlineVarStatement
+	var qualId *ast.QualId
+	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("VAR")) {
+		// The control variable is visible only in the for statement.
+		defer p.context.StackDeclMap()()
+		var err error
+		if forVar, qualId, err = p.parseForVar(); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if qualId, err = p.ParseQualId(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("IN")) {
+		p.NextToken()
+		enumerable, err := p.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.Current(token.ReservedWord.HasKeyword("DO")); err != nil {
+			return nil, err
+		}
+		p.NextToken()
+		statement, err := p.ParseStatement()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ForInStmt{
+			QualId:     qualId,
+			Var:        forVar,
+			Enumerable: enumerable,
+			Statement:  statement,
+		}, nil
 	}
+
 	if _, err := p.Current(token.Symbol(':', '=')); err != nil {
 		return nil, err
 	}
@@ -93,9 +129,35 @@ func (p *Parser) ParseForStmt() (*ast.ForStmt, error) {
 	}
 	return &ast.ForStmt{
 		QualId:    qualId,
+		Var:       forVar,
 		Initial:   initial,
 		Terminal:  terminal,
 		Down:      down,
 		Statement: statement,
 	}, nil
 }
+
+// parseForVar parses the control variable declared by FOR VAR,
+// and returns it with QualId which refers it.
+func (p *Parser) parseForVar() (*ast.InlineVarStatement, *ast.QualId, error) {
+	t, err := p.Next(token.Identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	ident := p.NewIdent(t)
+	res := &ast.InlineVarStatement{IdentList: ast.IdentList{ident}}
+	if p.NextToken().Is(token.Symbol(':')) {
+		p.NextToken()
+		typ, err := p.ParseType()
+		if err != nil {
+			return nil, nil, err
+		}
+		res.Type = typ
+	} else {
+		res.Inferred = true
+	}
+	if err := p.context.Set(res); err != nil {
+		return nil, nil, err
+	}
+	return res, ast.NewQualId(nil, ast.NewIdentRef(ident, res.ToDeclarations()[0])), nil
+}
@@ -50,7 +50,7 @@ func (p *Parser) ParseExceptionBlock() (*ast.ExceptionBlock, error) {
 	kwEnd := token.ReservedWord.HasKeyword("END")
 	// ON is NOT a reserved word
 	// If there is no "ON" at the head, then the exception block is else statements only
-	if !p.CurrentToken().Is(token.UpperCase("ON")) {
+	if !p.isExceptionHandlerHead() {
 		statements, err := p.ParseStmtList(kwEnd)
 		if err != nil {
 			return nil, err
@@ -106,14 +106,26 @@ func (p *Parser) ParseExceptionBlockHandlers() (ast.ExceptionBlockHandlers, erro
 			p.NextToken()
 		}
 
-		// ON is NOT a reserved word
-		if !p.CurrentToken().Is(token.UpperCase("ON")) {
+		if !p.isExceptionHandlerHead() {
 			break
 		}
 	}
 	return res, nil
 }
 
+// isExceptionHandlerHead returns true if the current token is ON which starts
+// an exception handler. ON is NOT a reserved word, so it is followed by the
+// identifier of the exception or its type unless it is used as an identifier
+// like `On := True;`.
+func (p *Parser) isExceptionHandlerHead() bool {
+	if !p.CurrentToken().Is(token.UpperCase("ON")) {
+		return false
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	return p.NextToken().Is(token.Identifier)
+}
+
 func (p *Parser) ParseExceptionBlockHandler() (*ast.ExceptionBlockHandler, error) {
 	// ON is NOT a reserved word
 	if _, err := p.Current(token.UpperCase("ON")); err != nil {
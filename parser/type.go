@@ -2,8 +2,8 @@ package parser
 
 import (
 	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
-	"github.com/pkg/errors"
 )
 
 func (p *Parser) ParseTypeSection(required bool) (ast.TypeSection, error) {
@@ -19,6 +19,7 @@ func (p *Parser) ParseTypeSection(required bool) (ast.TypeSection, error) {
 	}
 	defer p.SetupPostSectionFuncs()()
 
+	kwToken := p.CurrentToken()
 	p.NextToken()
 	res := ast.TypeSection{}
 	for {
@@ -26,6 +27,10 @@ func (p *Parser) ParseTypeSection(required bool) (ast.TypeSection, error) {
 		if err != nil {
 			return nil, err
 		}
+		if len(res) == 0 {
+			// The compiler directives before TYPE are attached to the first declaration.
+			decl.CompilerDirectives = append(p.leadingCompilerDirectives(kwToken), decl.CompilerDirectives...)
+		}
 		{
 			t := p.CurrentToken()
 			if t.Is(token.ReservedWord) || t.Is(token.EOF) {
@@ -50,34 +55,45 @@ func (p *Parser) ParseTypeSection(required bool) (ast.TypeSection, error) {
 func (p *Parser) ParseTypeDecl() (*ast.TypeDecl, error) {
 	defer p.TraceMethod("Parser.ParseTypeDecl")()
 
-	res := &ast.TypeDecl{}
-	ident, err := p.Current(token.Identifier)
+	directives := p.leadingCompilerDirectives(p.CurrentToken())
+	attrs, err := p.ParseAttributes()
 	if err != nil {
 		return nil, err
 	}
-	res.Ident = p.NewIdent(ident)
-	if _, err := p.Next(token.Symbol('=')); err != nil {
+	res := &ast.TypeDecl{Attributes: attrs, CompilerDirectives: directives}
+	ident, err := p.Current(token.Identifier)
+	if err != nil {
 		return nil, err
 	}
-	t := p.NextToken()
-	if t.Is(token.ReservedWord.HasKeyword("TYPE")) {
-		// TODO res.Typed = true
-		p.NextToken()
-	}
-	typ, err := p.ParseType()
+	res.Ident = p.NewIdent(ident)
+	typ, err := func() (ast.Type, error) {
+		if p.NextToken().Is(token.Symbol('<')) {
+			// Type parameters are visible only in the type declaration.
+			defer p.context.StackDeclMap()()
+			typeParams, err := p.ParseTypeParams()
+			if err != nil {
+				return nil, err
+			}
+			res.TypeParams = typeParams
+		}
+		if _, err := p.Current(token.Symbol('=')); err != nil {
+			return nil, err
+		}
+		t := p.NextToken()
+		if t.Is(token.ReservedWord.HasKeyword("TYPE")) {
+			// TODO res.Typed = true
+			p.NextToken()
+		}
+		return p.ParseType()
+	}()
 	if err != nil {
 		return nil, err
 	}
 	res.Type = typ
-	if old := p.context.Get(res.Ident.Name); old != nil {
-		typeDecl, ok := old.Node.(*ast.TypeDecl)
-		if !ok {
-			return nil, errors.Errorf("expects type declaration but was %T", old.Node)
-		}
-		fwd, ok := typeDecl.Type.(ast.ForwardDeclaration)
-		if !ok {
-			return nil, errors.Errorf("expects forward declaration but was %T", typeDecl.Type)
-		}
+	if classType := classMembersTypeOf(typ); classType != nil {
+		p.declaredTypes[classType] = true
+	}
+	if fwd := p.findForwardDeclaration(res.Ident.Name); fwd != nil {
 		if err := fwd.SetActualType(typ); err != nil {
 			return nil, err
 		}
@@ -89,6 +105,8 @@ func (p *Parser) ParseTypeDecl() (*ast.TypeDecl, error) {
 			return nil, err
 		}
 	}
+	registerHelper(p.context, res)
+	registerNestedTypes(p.context, res)
 
 	{
 		t := p.CurrentToken()
@@ -105,6 +123,25 @@ func (p *Parser) ParseTypeDecl() (*ast.TypeDecl, error) {
 	return res, nil
 }
 
+// findForwardDeclaration returns the forward declaration of the type named name.
+// Other declarations with the same name are not returned, because nested
+// types can hide them. Duplicated declarations are detected by the context.
+func (p *Parser) findForwardDeclaration(name string) ast.ForwardDeclaration {
+	old := p.context.Get(name)
+	if old == nil {
+		return nil
+	}
+	typeDecl, ok := old.Node.(*ast.TypeDecl)
+	if !ok {
+		return nil
+	}
+	fwd, ok := typeDecl.Type.(ast.ForwardDeclaration)
+	if !ok {
+		return nil
+	}
+	return fwd
+}
+
 func (p *Parser) ParseType() (ast.Type, error) {
 	t1 := p.CurrentToken()
 	switch t1.Type {
@@ -115,17 +152,28 @@ func (p *Parser) ParseType() (ast.Type, error) {
 			return p.ParseCustomPointerType()
 		}
 	case token.Identifier:
+		if p.isReferenceTo() {
+			return p.ParseProcedureType()
+		}
 		return p.ParseTypeForIdentifier()
 	case token.NumeralInt, token.NumeralReal, token.CharacterString:
 		return p.ParseConstSubrageType()
 	case token.ReservedWord:
 		switch t1.Value() {
 		case "PACKED", "ARRAY", "SET", "RECORD", "FILE":
+			if p.isRecordHelper() {
+				p.NextToken()
+				return p.ParseHelperType(ast.HkRecord)
+			}
 			return p.ParseStrucType()
 		case "FUNCTION", "PROCEDURE":
 			return p.ParseProcedureType()
 		case "CLASS":
 			return p.ParseClassTypeOrClassRefType()
+		case "OBJECT":
+			return p.ParseObjectType()
+		case "INTERFACE", "DISPINTERFACE":
+			return p.ParseInterfaceType()
 		default:
 			return p.ParseStringOfStringType()
 		}
@@ -169,13 +217,29 @@ func (p *Parser) parseTypeIdWithUnit() (*ast.TypeId, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &ast.TypeId{UnitId: unitId, Ident: p.NewIdent(t)}, nil
+	r := &ast.TypeId{UnitId: unitId, Ident: p.NewIdent(t)}
+	if p.NextToken().Is(token.Symbol('<')) {
+		typeArgs, err := p.ParseTypeArgs()
+		if err != nil {
+			return nil, err
+		}
+		r.TypeArgs = typeArgs
+	}
+	return r, nil
 }
 
 func (p *Parser) parseTypeIdWithoutUnit() (*ast.TypeId, error) {
 	ident := p.NewIdent(p.CurrentToken())
 	p.NextToken()
+	ident = p.parseNestedTypeIdent(ident)
 	r := &ast.TypeId{Ident: ident}
+	if p.CurrentToken().Is(token.Symbol('<')) {
+		typeArgs, err := p.ParseTypeArgs()
+		if err != nil {
+			return nil, err
+		}
+		r.TypeArgs = typeArgs
+	}
 
 	decl := p.context.Get(ident.Name)
 	if decl == nil {
@@ -193,6 +257,23 @@ func (p *Parser) parseTypeIdWithoutUnit() (*ast.TypeId, error) {
 	return r, nil
 }
 
+// parseNestedTypeIdent returns the ident with qualified name such as TOuter.TInner
+// if the identifiers following ident refer a nested type.
+func (p *Parser) parseNestedTypeIdent(ident *ast.Ident) *ast.Ident {
+	for p.CurrentToken().Is(token.Symbol('.')) {
+		rollback := p.RollbackPoint()
+		t := p.NextToken()
+		name := ident.Name + "." + t.RawString()
+		if !t.Is(token.Identifier) || p.context.Get(name) == nil {
+			rollback()
+			break
+		}
+		ident = &ast.Ident{Name: name, Location: astcore.NewLocation(ident.Location.Start, t.End)}
+		p.NextToken()
+	}
+	return ident
+}
+
 func (p *Parser) ParseCustomPointerType() (*ast.CustomPointerType, error) {
 	if _, err := p.Current(token.Symbol('^')); err != nil {
 		return nil, err
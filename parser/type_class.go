@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
 )
 
@@ -18,6 +19,9 @@ func (p *Parser) ParseClassTypeOrClassRefType() (ast.Type, error) {
 	if p.CurrentToken().Is(token.Symbol(';')) {
 		return &ast.ForwardDeclaredClassType{}, nil
 	}
+	if p.CurrentToken().Is(p.contextualKeyword(token.UpperCase("HELPER"))) {
+		return p.ParseHelperType(ast.HkClass)
+	}
 
 	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("OF")) {
 		t, err := p.Next(token.Identifier)
@@ -44,6 +48,14 @@ func (p *Parser) ParseClassType() (ast.ClassType, error) {
 	defer p.TraceMethod("Parser.ParseClassType")()
 
 	res := &ast.CustomClassType{}
+	switch t := p.CurrentToken(); {
+	case t.Is(p.contextualKeyword(token.UpperCase("ABSTRACT"))):
+		res.Abstract = true
+		p.NextToken()
+	case t.Is(p.contextualKeyword(token.UpperCase("SEALED"))):
+		res.Sealed = true
+		p.NextToken()
+	}
 	if heritage, err := p.ParseClassHeritage(); err != nil {
 		return nil, err
 	} else {
@@ -60,6 +72,29 @@ func (p *Parser) ParseClassType() (ast.ClassType, error) {
 	return res, nil
 }
 
+func (p *Parser) ParseObjectType() (ast.ObjectType, error) {
+	defer p.TraceMethod("Parser.ParseObjectType")()
+
+	if _, err := p.Current(token.ReservedWord.HasKeyword("OBJECT")); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	res := &ast.CustomObjectType{}
+	if heritage, err := p.ParseClassHeritage(); err != nil {
+		return nil, err
+	} else {
+		res.Heritage = heritage
+	}
+	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("END")) {
+		return res, nil
+	}
+	if _, err := p.ParseClassMemberSections(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (p *Parser) ParseClassHeritage() (ast.ClassHeritage, error) {
 	defer p.TraceMethod("Parser.ParseClassHeritage")()
 
@@ -83,109 +118,267 @@ func (p *Parser) ParseClassHeritage() (ast.ClassHeritage, error) {
 	return res, nil
 }
 
-func (p *Parser) ParseClassMemberSections(classType *ast.CustomClassType) (ast.ClassMemberSections, error) {
+func (p *Parser) ParseClassMemberSections(classType ast.ClassMembersType) (ast.ClassMemberSections, error) {
 	defer p.TraceMethod("Parser.ParseClassMemberSections")()
 
-	classType.Members = ast.ClassMemberSections{}
+	// Nested constants and types are visible only in the type.
+	defer p.context.StackDeclMap()()
+
+	res := ast.ClassMemberSections{}
 	if err := p.Until(token.ReservedWord.HasKeyword("END"), nil, func() error {
-		if _, err := p.ParseClassMemberSection(classType); err != nil {
+		section, err := p.ParseClassMemberSection(classType)
+		if err != nil {
 			return err
 		}
+		res = append(res, section)
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return classType.Members, nil
+	return res, nil
 }
 
-func (p *Parser) ParseClassMemberSection(classType *ast.CustomClassType) (*ast.ClassMemberSection, error) {
+func (p *Parser) ParseClassMemberSection(classType ast.ClassMembersType) (*ast.ClassMemberSection, error) {
 	defer p.TraceMethod("Parser.ParseClassMemberSection")()
 
 	res := &ast.ClassMemberSection{}
-	classType.Members = append(classType.Members, res)
-
-	if t0, err := p.Current(token.Identifier); err != nil {
-		return nil, err
-	} else {
-		switch strings.ToUpper(t0.Value()) {
-		case "PRIVATE":
-			res.Visibility = ast.CvPrivate
-			p.NextToken()
-		case "PROTECTED":
-			res.Visibility = ast.CvProtected
-			p.NextToken()
-		case "PUBLIC":
-			res.Visibility = ast.CvPublic
+	// The section is added before its members are parsed so that
+	// properties can refer the fields and methods declared before them.
+	classType.AddMemberSection(res)
+
+	// Sections without visibility can start with methods, properties or nested declarations.
+	visibility := ""
+	if p.CurrentToken().Is(p.visibilityBreak()) {
+		visibility = strings.ToUpper(p.CurrentToken().Value())
+	}
+	switch visibility {
+	case "PRIVATE":
+		res.Visibility = ast.CvPrivate
+		p.NextToken()
+	case "PROTECTED":
+		res.Visibility = ast.CvProtected
+		p.NextToken()
+	case "PUBLIC":
+		res.Visibility = ast.CvPublic
+		p.NextToken()
+	case "PUBLISHED":
+		res.Visibility = ast.CvPublished
+		p.NextToken()
+	case "STRICT":
+		t, err := p.Next(token.Some(token.UpperCase("PRIVATE"), token.UpperCase("PROTECTED")))
+		if err != nil {
+			return nil, err
+		}
+		if strings.ToUpper(t.Value()) == "PRIVATE" {
+			res.Visibility = ast.CvStrictPrivate
+		} else {
+			res.Visibility = ast.CvStrictProtected
+		}
+		p.NextToken()
+	default:
+		res.Visibility = ast.CvDefault
+	}
+
+	propertyBreak := p.propertyBreak()
+	for !p.CurrentToken().Is(propertyBreak) {
+		// Attributes are parsed with the member which they belong to.
+		t := p.tokenAfterAttributes()
+		switch {
+		case t.Is(token.ReservedWord.HasKeyword("CONST")):
+			sect, err := p.parseNestedConstSection()
+			if err != nil {
+				return nil, err
+			}
+			res.NestedDeclSections = append(res.NestedDeclSections, sect)
+		case t.Is(token.ReservedWord.HasKeyword("TYPE")):
+			sect, err := p.parseNestedTypeSection()
+			if err != nil {
+				return nil, err
+			}
+			res.NestedDeclSections = append(res.NestedDeclSections, sect)
+		case t.Is(token.ReservedWord.HasKeyword("PROPERTY")):
+			propList, err := p.ParseClassPropertyList(classType)
+			if err != nil {
+				return nil, err
+			}
+			res.ClassPropertyList = append(res.ClassPropertyList, propList...)
+		case p.isClassVar():
 			p.NextToken()
-		case "PUBLISHED":
-			res.Visibility = ast.CvPublished
 			p.NextToken()
+			fieldList, err := p.ParseClassFieldList()
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range fieldList {
+				field.ClassVar = true
+			}
+			res.ClassFieldList = append(res.ClassFieldList, fieldList...)
+		case t.Is(methodStart):
+			methodList, err := p.ParseClassMethodList()
+			if err != nil {
+				return nil, err
+			}
+			res.ClassMethodList = append(res.ClassMethodList, methodList...)
 		default:
-			res.Visibility = ast.CvDefault
+			// Fields after nested declarations must be started with VAR.
+			if p.CurrentToken().Is(token.ReservedWord.HasKeyword("VAR")) {
+				p.NextToken()
+			}
+			fieldList, err := p.ParseClassFieldList()
+			if err != nil {
+				return nil, err
+			}
+			res.ClassFieldList = append(res.ClassFieldList, fieldList...)
 		}
 	}
-	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("END")) {
-		return res, nil
-	}
 
-	fieldList, err := p.ParseClassFieldList()
-	if err != nil {
-		return nil, err
+	return res, nil
+}
+
+// registerNestedTypes puts the nested types of typeDecl into declMap with
+// the qualified names such as TOuter.TInner, so that they can be referred
+// from outside of the type.
+func registerNestedTypes(declMap astcore.DeclMap, typeDecl *ast.TypeDecl) {
+	registerNestedTypesWithPrefix(declMap, typeDecl.Ident.Name, typeDecl)
+}
+
+func registerNestedTypesWithPrefix(declMap astcore.DeclMap, prefix string, typeDecl *ast.TypeDecl) {
+	classType := classMembersTypeOf(typeDecl.Type)
+	if classType == nil {
+		return
 	}
-	if len(fieldList) > 0 {
-		res.ClassFieldList = fieldList
+	for _, decl := range classType.MemberDecls(&ast.MemberAccess{Type: classType}) {
+		if nested, ok := decl.Node.(*ast.TypeDecl); ok && nested != typeDecl {
+			name := prefix + "." + decl.Ident.Name
+			declMap.Overwrite(name, decl)
+			registerNestedTypesWithPrefix(declMap, name, nested)
+		}
 	}
+}
 
-	methodList, err := p.ParseClassMethodList()
-	if err != nil {
+// isClassVar returns true if the current CLASS starts CLASS VAR fields.
+func (p *Parser) isClassVar() bool {
+	if !p.CurrentToken().Is(token.ReservedWord.HasKeyword("CLASS")) {
+		return false
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	return p.NextToken().Is(token.ReservedWord.HasKeyword("VAR"))
+}
+
+// parseNestedConstSection parses CONST section in class, object and record types,
+// which ends before reserved words or visibility specifiers.
+func (p *Parser) parseNestedConstSection() (ast.ConstSection, error) {
+	if _, err := p.Current(token.ReservedWord.HasKeyword("CONST")); err != nil {
 		return nil, err
 	}
-	if len(methodList) > 0 {
-		res.ClassMethodList = methodList
+	p.NextToken()
+	res := ast.ConstSection{}
+	for {
+		decl, err := p.ParseConstantDecl()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.Current(token.Symbol(';')); err != nil {
+			return nil, err
+		}
+		res = append(res, decl)
+		p.NextToken()
+		if p.tokenAfterAttributes().Is(p.nestedDeclBreak()) {
+			break
+		}
 	}
+	return res, nil
+}
 
-	propList, err := p.ParseClassPropertyList(classType)
-	if err != nil {
+// parseNestedTypeSection parses TYPE section in class, object and record types,
+// which ends before reserved words or visibility specifiers.
+func (p *Parser) parseNestedTypeSection() (ast.TypeSection, error) {
+	if _, err := p.Current(token.ReservedWord.HasKeyword("TYPE")); err != nil {
 		return nil, err
 	}
-	if len(propList) > 0 {
-		res.ClassPropertyList = propList
+	p.NextToken()
+	res := ast.TypeSection{}
+	for {
+		decl, err := p.ParseTypeDecl()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.Current(token.Symbol(';')); err != nil {
+			return nil, err
+		}
+		res = append(res, decl)
+		p.NextToken()
+		if p.tokenAfterAttributes().Is(p.nestedDeclBreak()) {
+			break
+		}
 	}
-
 	return res, nil
 }
 
 var (
-	visibilityBreak = token.Some(
+	visibilityWords = token.Some(
 		token.UpperCase("PRIVATE"),
 		token.UpperCase("PROTECTED"),
 		token.UpperCase("PUBLIC"),
 		token.UpperCase("PUBLISHED"),
+		token.UpperCase("STRICT"),
 	)
-
-	propertyBreak = token.Some(
-		visibilityBreak,
-		token.ReservedWord.HasKeyword("END"),
-	)
-	methodBreak = token.Some(
-		token.ReservedWord.HasKeyword("PROPERTY"),
-		propertyBreak,
-	)
-	fieldListBreak = token.Some(
+	methodStart = token.Some(
+		token.ReservedWord.HasKeyword("CLASS"),
 		token.ReservedWord.HasKeyword("FUNCTION"),
 		token.ReservedWord.HasKeyword("PROCEDURE"),
 		token.ReservedWord.HasKeyword("CONSTRUCTOR"),
 		token.ReservedWord.HasKeyword("DESTRUCTOR"),
-		methodBreak,
 	)
 )
 
+// The visibility words are not reserved words, so they can be the identifiers
+// of fields like `Public: Boolean;`. The following predicators don't match them
+// in that case.
+
+func (p *Parser) visibilityBreak() token.Predicator {
+	return p.contextualKeyword(visibilityWords)
+}
+
+func (p *Parser) propertyBreak() token.Predicator {
+	return token.Some(
+		p.visibilityBreak(),
+		token.ReservedWord.HasKeyword("END"),
+	)
+}
+
+func (p *Parser) methodBreak() token.Predicator {
+	return token.Some(
+		token.ReservedWord.HasKeyword("PROPERTY"),
+		token.ReservedWord.HasKeyword("CONST"),
+		token.ReservedWord.HasKeyword("TYPE"),
+		token.ReservedWord.HasKeyword("VAR"),
+		p.propertyBreak(),
+	)
+}
+
+func (p *Parser) fieldListBreak() token.Predicator {
+	return token.Some(
+		methodStart,
+		p.methodBreak(),
+	)
+}
+
+func (p *Parser) nestedDeclBreak() token.Predicator {
+	return token.Some(
+		token.ReservedWord,
+		p.visibilityBreak(),
+	)
+}
+
 func (p *Parser) ParseClassFieldList() (ast.ClassFieldList, error) {
 	defer p.TraceMethod("Parser.ParseClassFieldList")()
 
 	res := ast.ClassFieldList{}
+	fieldListBreak := p.fieldListBreak()
 	if err := p.Until(fieldListBreak, token.Symbol(';'), func() error {
-		if fieldListBreak.Predicate(p.CurrentToken()) {
+		if fieldListBreak.Predicate(p.tokenAfterAttributes()) {
 			return QuitUntil
 		}
 		field, err := p.ParseClassField()
@@ -204,6 +397,10 @@ func (p *Parser) ParseClassFieldList() (ast.ClassFieldList, error) {
 func (p *Parser) ParseClassField() (*ast.ClassField, error) {
 	defer p.TraceMethod("Parser.ParseClassField")()
 
+	attrs, err := p.ParseAttributes()
+	if err != nil {
+		return nil, err
+	}
 	identList, err := p.ParseIdentList(':')
 	if err != nil {
 		return nil, err
@@ -214,7 +411,7 @@ func (p *Parser) ParseClassField() (*ast.ClassField, error) {
 	if err != nil {
 		return nil, err
 	}
-	res := &ast.ClassField{IdentList: *identList, Type: typ}
+	res := &ast.ClassField{Attributes: attrs, IdentList: *identList, Type: typ}
 	return res, nil
 }
 
@@ -222,8 +419,9 @@ func (p *Parser) ParseClassMethodList() (ast.ClassMethodList, error) {
 	defer p.TraceMethod("Parser.ParseClassMethodList")()
 
 	res := ast.ClassMethodList{}
+	methodBreak := p.methodBreak()
 	if err := p.Until(methodBreak, nil, func() error {
-		if methodBreak.Predicate(p.CurrentToken()) {
+		if methodBreak.Predicate(p.tokenAfterAttributes()) {
 			return QuitUntil
 		}
 		method, err := p.ParseClassMethod()
@@ -244,11 +442,25 @@ func (p *Parser) ParseClassMethodList() (ast.ClassMethodList, error) {
 func (p *Parser) ParseClassMethod() (*ast.ClassMethod, error) {
 	defer p.TraceMethod("Parser.ParseClassMethod")()
 
-	res := &ast.ClassMethod{}
+	attrs, err := p.ParseAttributes()
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.ClassMethod{Attributes: attrs}
 	t0, err := p.Current(token.ReservedWord)
 	if err != nil {
 		return nil, err
 	}
+	if t0.Is(token.ReservedWord.HasKeyword("CLASS")) {
+		res.ClassMethod = true
+		if t0, err = p.Next(token.Some(
+			token.ReservedWord.HasKeyword("FUNCTION"),
+			token.ReservedWord.HasKeyword("PROCEDURE"),
+			token.UpperCase("OPERATOR"),
+		)); err != nil {
+			return nil, err
+		}
+	}
 
 	defer p.context.StackDeclMap()()
 
@@ -265,6 +477,12 @@ func (p *Parser) ParseClassMethod() (*ast.ClassMethod, error) {
 			return nil, err
 		}
 		res.Heading = heading
+	case "OPERATOR":
+		heading, err := p.ParseOperatorHeading()
+		if err != nil {
+			return nil, err
+		}
+		res.Heading = heading
 	case "DESTRUCTOR":
 		heading, err := p.ParseDestructorHeading()
 		if err != nil {
@@ -275,18 +493,23 @@ func (p *Parser) ParseClassMethod() (*ast.ClassMethod, error) {
 		return nil, fmt.Errorf("unexpected token for method: %s", t0)
 	}
 
-	directiveList, err := p.ClassMethodDirectiveList()
+	directiveList, message, err := p.ClassMethodDirectiveList()
 	if err != nil {
 		return nil, err
 	}
 	if len(directiveList) > 0 {
 		res.Directives = directiveList
 	}
+	res.Message = message
 
 	return res, nil
 }
 
-func (p *Parser) ClassMethodDirectiveList() (ast.ClassMethodDirectiveList, error) {
+// ClassMethodDirectiveList parses the directives after ClassMethodHeading.
+// It returns the message ID too if MESSAGE directive is given.
+// The directives are not reserved words, so they are not directives if they
+// are used as identifiers.
+func (p *Parser) ClassMethodDirectiveList() (ast.ClassMethodDirectiveList, *ast.ConstExpr, error) {
 	if p.CurrentToken().Is(token.Symbol(';')) {
 		p.NextToken()
 	}
@@ -294,19 +517,26 @@ func (p *Parser) ClassMethodDirectiveList() (ast.ClassMethodDirectiveList, error
 	defer p.TraceMethod("Parser.ClassMethodDirectiveList")()
 
 	res := ast.ClassMethodDirectiveList{}
-	if err := p.Until(methodBreak, token.Symbol(';'), func() error {
+	var message *ast.ConstExpr
+	if err := p.Until(p.methodBreak(), token.Symbol(';'), func() error {
 		w := strings.ToUpper(p.CurrentToken().Value())
-		if ast.ClassMethodDirectives.Include(w) {
-			res = append(res, ast.ClassMethodDirective(w))
-			p.NextToken()
-			return nil
-		} else {
+		if !ast.ClassMethodDirectives.Include(w) || p.isUsedAsIdent() {
 			return QuitUntil
 		}
+		res = append(res, ast.ClassMethodDirective(w))
+		p.NextToken()
+		if ast.ClassMethodDirective(w) == ast.CmdMessage {
+			expr, err := p.ParseConstExpr()
+			if err != nil {
+				return err
+			}
+			message = expr
+		}
+		return nil
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return res, nil
+	return res, message, nil
 }
 
 func (p *Parser) ParseConstructorHeading() (*ast.ConstructorHeading, error) {
@@ -345,12 +575,47 @@ func (p *Parser) ParseDestructorHeading() (*ast.DestructorHeading, error) {
 	return res, nil
 }
 
-func (p *Parser) ParseClassPropertyList(classType *ast.CustomClassType) (ast.ClassPropertyList, error) {
+func (p *Parser) ParseOperatorHeading() (*ast.OperatorHeading, error) {
+	defer p.TraceMethod("Parser.ParseOperatorHeading")()
+
+	if _, err := p.Current(token.UpperCase("OPERATOR")); err != nil {
+		return nil, err
+	}
+	res := &ast.OperatorHeading{}
+
+	t0, err := p.Next(token.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	res.Ident = p.NewIdent(t0)
+
+	p.NextToken()
+	if p.CurrentToken().Is(token.Symbol('(')) {
+		params, err := p.ParseFormalParameters('(', ')')
+		if err != nil {
+			return nil, err
+		}
+		res.FormalParameters = params
+	}
+	if _, err := p.Current(token.Symbol(':')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	typ, err := p.ParseTypeId()
+	if err != nil {
+		return nil, err
+	}
+	res.ReturnType = typ
+	return res, nil
+}
+
+func (p *Parser) ParseClassPropertyList(classType ast.ClassMembersType) (ast.ClassPropertyList, error) {
 	defer p.TraceMethod("Parser.ParseClassPropertyList")()
 
+	propertyStart := token.ReservedWord.HasKeyword("PROPERTY")
 	res := ast.ClassPropertyList{}
-	if err := p.Until(propertyBreak, nil, func() error {
-		if propertyBreak.Predicate(p.CurrentToken()) {
+	if err := p.Until(token.Not(propertyStart), nil, func() error {
+		if !propertyStart.Predicate(p.tokenAfterAttributes()) {
 			return QuitUntil
 		}
 		prop, err := p.ParseClassProperty(classType)
@@ -368,11 +633,15 @@ func (p *Parser) ParseClassPropertyList(classType *ast.CustomClassType) (ast.Cla
 	return res, nil
 }
 
-func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassProperty, error) {
+func (p *Parser) ParseClassProperty(classType ast.ClassMembersType) (*ast.ClassProperty, error) {
+	attrs, err := p.ParseAttributes()
+	if err != nil {
+		return nil, err
+	}
 	if _, err := p.Current(token.ReservedWord.HasKeyword("PROPERTY")); err != nil {
 		return nil, err
 	}
-	res := &ast.ClassProperty{}
+	res := &ast.ClassProperty{Attributes: attrs}
 
 	p.Logf("Parser.ParseClassProperty #01")
 
@@ -414,7 +683,7 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 	if strings.ToUpper(p.CurrentToken().Value()) == "READ" {
 		p.Logf("Parser.ParseClassProperty #07")
 		t := p.NextToken()
-		if decl := classType.FindMemberDecl(t.Value(), true); decl != nil {
+		if decl := classType.FindMemberDecl(t.Value(), p.memberAccess(classType)); decl != nil {
 			res.Read = ast.NewIdentRef(ast.NewIdent(t), decl)
 		} else {
 			p.Logf("Parser.ParseClassProperty #08")
@@ -428,7 +697,7 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 	if strings.ToUpper(p.CurrentToken().Value()) == "WRITE" {
 		p.Logf("Parser.ParseClassProperty #10")
 		t := p.NextToken()
-		if decl := classType.FindMemberDecl(t.Value(), true); decl != nil {
+		if decl := classType.FindMemberDecl(t.Value(), p.memberAccess(classType)); decl != nil {
 			res.Write = ast.NewIdentRef(ast.NewIdent(t), decl)
 		} else {
 			p.Logf("Parser.ParseClassProperty #11")
@@ -449,7 +718,7 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 		} else if tVal == "false" {
 			v := false
 			res.Stored = &ast.PropertyStoredSpecifier{Constant: &v}
-		} else if decl := classType.FindMemberDecl(tVal, true); decl != nil {
+		} else if decl := classType.FindMemberDecl(tVal, p.memberAccess(classType)); decl != nil {
 			res.Stored = &ast.PropertyStoredSpecifier{IdentRef: ast.NewIdentRef(ast.NewIdent(t), decl)}
 		} else {
 			p.Logf("Parser.ParseClassProperty #14")
@@ -473,6 +742,7 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 		p.Logf("Parser.ParseClassProperty #18")
 		v := true
 		res.Default = &ast.PropertyDefaultSpecifier{NoDefault: &v}
+		p.NextToken()
 	}
 
 	p.Logf("Parser.ParseClassProperty #19")
@@ -480,6 +750,7 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 	//   [IMPLEMENTS TypeId]
 	if strings.ToUpper(p.CurrentToken().Value()) == "IMPLEMENTS" {
 		p.Logf("Parser.ParseClassProperty #20")
+		p.NextToken()
 		typeId, err := p.ParseTypeId()
 		if err != nil {
 			p.Logf("Parser.ParseClassProperty #21")
@@ -500,9 +771,30 @@ func (p *Parser) ParseClassProperty(classType *ast.CustomClassType) (*ast.ClassP
 	//   [PortabilityDirective]
 	//    TODO
 
+	//   [';' DEFAULT]
+	if p.isDefaultPropertyDirective() {
+		p.NextToken()
+		p.NextToken()
+		res.DefaultProperty = true
+	}
+
 	return res, nil
 }
 
+// isDefaultPropertyDirective returns true if the current ';' is followed by
+// DEFAULT and ';' which make the array property the default property.
+func (p *Parser) isDefaultPropertyDirective() bool {
+	if !p.CurrentToken().Is(token.Symbol(';')) {
+		return false
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	if !p.NextToken().Is(token.UpperCase("DEFAULT")) {
+		return false
+	}
+	return p.NextToken().Is(token.Symbol(';'))
+}
+
 func (p *Parser) ParsePropertyInterface() (*ast.PropertyInterface, error) {
 	res := &ast.PropertyInterface{}
 	if p.CurrentToken().Is(token.Symbol('[')) {
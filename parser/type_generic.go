@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/token"
+)
+
+// ParseTypeParams parses TypeParams and declares each TypeParam in the current scope
+// so that the following constraints, types and blocks can refer them.
+func (p *Parser) ParseTypeParams() (ast.TypeParams, error) {
+	defer p.TraceMethod("Parser.ParseTypeParams")()
+
+	if _, err := p.Current(token.Symbol('<')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	res := ast.TypeParams{}
+	if err := p.Until(token.Symbol('>'), token.Symbol(';'), func() error {
+		params, err := p.parseTypeParamDecl()
+		if err != nil {
+			return err
+		}
+		res = append(res, params...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return res, nil
+}
+
+func (p *Parser) parseTypeParamDecl() (ast.TypeParams, error) {
+	res := ast.TypeParams{}
+	if err := p.Until(token.Not(token.Symbol(',')), token.Symbol(','), func() error {
+		t, err := p.Current(token.Identifier)
+		if err != nil {
+			return err
+		}
+		res = append(res, &ast.TypeParam{Ident: p.NewIdent(t)})
+		p.NextToken()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if p.CurrentToken().Is(token.Symbol(':')) {
+		p.NextToken()
+		constraints := ast.TypeConstraints{}
+		if err := p.Until(token.Not(token.Symbol(',')), token.Symbol(','), func() error {
+			constraint, err := p.ParseTypeConstraint()
+			if err != nil {
+				return err
+			}
+			constraints = append(constraints, constraint)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		for _, param := range res {
+			param.Constraints = constraints
+		}
+	}
+
+	for _, param := range res {
+		if err := p.context.Set(param); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (p *Parser) ParseTypeConstraint() (ast.TypeConstraint, error) {
+	t := p.CurrentToken()
+	if t.Is(token.ReservedWord) {
+		switch t.Value() {
+		case "CLASS":
+			p.NextToken()
+			return ast.CkClass, nil
+		case "RECORD":
+			p.NextToken()
+			return ast.CkRecord, nil
+		case "CONSTRUCTOR":
+			p.NextToken()
+			return ast.CkConstructor, nil
+		}
+		return nil, p.TokenErrorf("expects CLASS, RECORD, CONSTRUCTOR or type identifier, but got %s", t)
+	}
+	return p.ParseTypeId()
+}
+
+func (p *Parser) ParseTypeArgs() (ast.TypeArgs, error) {
+	if _, err := p.Current(token.Symbol('<')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	res := ast.TypeArgs{}
+	if err := p.Until(token.Symbol('>'), token.Symbol(','), func() error {
+		typ, err := p.ParseType()
+		if err != nil {
+			return err
+		}
+		res = append(res, typ)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return res, nil
+}
+
+// parseTypeArgsInExpression parses TypeArgs following an identifier in expressions.
+// '<' can be also the relational operator, so it rolls back and returns nil
+// unless the TypeArgs are followed by a token which can't start an operand.
+func (p *Parser) parseTypeArgsInExpression() ast.TypeArgs {
+	if !p.CurrentToken().Is(token.Symbol('<')) {
+		return nil
+	}
+	rollback := p.RollbackPoint()
+	res, err := p.ParseTypeArgs()
+	if err != nil || !p.CurrentToken().Is(typeArgsFollower) {
+		rollback()
+		return nil
+	}
+	return res
+}
+
+var typeArgsFollower = token.Some(
+	token.EOF,
+	token.ReservedWord,
+	token.Symbol('('),
+	token.Symbol(')'),
+	token.Symbol('.'),
+	token.Symbol(','),
+	token.Symbol(';'),
+	token.Symbol(']'),
+	token.Symbol('^'),
+)
+
+// isGenericDecl returns true if the declaration can be specialized with TypeArgs.
+// Unknown declarations are treated as generic.
+func isGenericDecl(decl *astcore.Decl) bool {
+	if decl == nil {
+		return true
+	}
+	switch v := decl.Node.(type) {
+	case *ast.TypeDecl:
+		return v.TypeParams != nil
+	case *ast.FunctionDecl:
+		return v.TypeParams != nil
+	case *ast.ClassMethod:
+		if heading, ok := v.Heading.(*ast.FunctionHeading); ok {
+			return heading.TypeParams != nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/ast/astcore"
+	"github.com/akm/tparser/token"
+)
+
+// ParseHelperType parses HelperType after CLASS or RECORD.
+// The current token must be HELPER.
+func (p *Parser) ParseHelperType(kind ast.HelperKind) (*ast.HelperType, error) {
+	defer p.TraceMethod("Parser.ParseHelperType")()
+
+	if _, err := p.Current(token.UpperCase("HELPER")); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	res := &ast.HelperType{Kind: kind}
+	if heritage, err := p.ParseClassHeritage(); err != nil {
+		return nil, err
+	} else {
+		res.Heritage = heritage
+	}
+
+	if _, err := p.Current(token.ReservedWord.HasKeyword("FOR")); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	// STRING is a reserved word but it can be extended by record helpers.
+	if typ, err := p.ParseStringType(false); err != nil {
+		return nil, err
+	} else if typ != nil {
+		res.For = typ.(*ast.TypeId)
+	} else if typeId, err := p.ParseTypeId(); err != nil {
+		return nil, err
+	} else {
+		res.For = typeId
+	}
+
+	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("END")) {
+		return res, nil
+	}
+	if _, err := p.ParseClassMemberSections(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// isRecordHelper returns true if the current RECORD starts HelperType.
+func (p *Parser) isRecordHelper() bool {
+	if !p.CurrentToken().Is(token.ReservedWord.HasKeyword("RECORD")) {
+		return false
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	p.NextToken()
+	return p.CurrentToken().Is(p.contextualKeyword(token.UpperCase("HELPER")))
+}
+
+// registerHelper puts the helper declaration into declMap with the key for the
+// helped type. A helper registered later overwrites the former one in the same
+// scope, and the helpers in the units used later are found first because
+// their DeclMaps are searched in reverse order of uses clause.
+func registerHelper(declMap astcore.DeclMap, typeDecl *ast.TypeDecl) {
+	helper, ok := typeDecl.Type.(*ast.HelperType)
+	if !ok || helper.For == nil {
+		return
+	}
+	declMap.Overwrite(ast.HelperDeclKey(helper.For.Ident.Name), typeDecl.ToDeclarations()[0])
+}
+
+// findHelper returns the helper in scope for typ, its aliases or its ancestor classes.
+func (p *Parser) findHelper(typ ast.Type) *ast.HelperType {
+	for typ != nil {
+		typeId, ok := typ.(*ast.TypeId)
+		if !ok {
+			return nil
+		}
+		if decl := p.context.Get(ast.HelperDeclKey(typeId.Ident.Name)); decl != nil {
+			if typeDecl, ok := decl.Node.(*ast.TypeDecl); ok {
+				if helper, ok := typeDecl.Type.(*ast.HelperType); ok {
+					return helper
+				}
+			}
+		}
+		if typeId.Ref == nil {
+			return nil
+		}
+		typeDecl, ok := typeId.Ref.Node.(*ast.TypeDecl)
+		if !ok || typeDecl.Type == typ {
+			return nil
+		}
+		typ = typeDecl.Type
+		if classType, ok := typ.(*ast.CustomClassType); ok {
+			if len(classType.Heritage) == 0 {
+				return nil
+			}
+			typ = classType.Heritage[0]
+		}
+	}
+	return nil
+}
+
+// findMemberDecl returns the declaration of the member named name of typ.
+// The helper in scope for typ is consulted when typ doesn't have the member.
+// The members which can't be accessed from the current method are not found.
+func (p *Parser) findMemberDecl(typ ast.Type, name string) *astcore.Decl {
+	if typ == nil {
+		return nil
+	}
+	access := p.memberAccess(nil)
+	actual := actualType(typ)
+	if helper, ok := actual.(*ast.HelperType); ok {
+		// Self in the methods of helpers
+		if r := helper.FindMemberDecl(name, access); r != nil {
+			return r
+		}
+		return p.findMemberDecl(helper.For, name)
+	}
+	if classType := classMembersTypeOf(actual); classType != nil {
+		if r := classType.FindMemberDecl(name, access); r != nil {
+			return r
+		}
+	}
+	if helper := p.findHelper(typ); helper != nil {
+		return helper.FindMemberDecl(name, access)
+	}
+	return nil
+}
+
+// memberAccess returns MemberAccess from typ in the text being parsed.
+// If typ is nil, the type of Self in the current method is used.
+func (p *Parser) memberAccess(typ ast.ClassMembersType) *ast.MemberAccess {
+	if typ == nil {
+		if decl := p.context.Get("Self"); decl != nil {
+			if self, ok := decl.Node.(*ast.Self); ok && self.TypeDecl != nil {
+				typ = classMembersTypeOf(self.TypeDecl.Type)
+			}
+		}
+	}
+	return &ast.MemberAccess{
+		Type: typ,
+		InUnit: func(t ast.ClassMembersType) bool {
+			return p.declaredTypes[t]
+		},
+	}
+}
+
+// declTypeOf returns the type of the value which decl declares.
+// It returns nil if the type is unknown.
+func declTypeOf(decl *astcore.Decl) ast.Type {
+	if decl == nil {
+		return nil
+	}
+	switch v := decl.Node.(type) {
+	case *ast.VarDecl:
+		return v.Type
+	case *ast.InlineVarStatement:
+		return v.Type
+	case *ast.FormalParm:
+		if v.Parameter.Type != nil && !v.Parameter.Type.IsArray {
+			return v.Parameter.Type.Type
+		}
+	case *ast.FieldDecl:
+		return v.Type
+	case *ast.ClassField:
+		return v.Type
+	case *ast.ConstantDecl:
+		return v.Type
+	case *ast.ClassProperty:
+		if v.Interface != nil && v.Interface.Type != nil {
+			return v.Interface.Type
+		}
+	case *ast.FunctionDecl:
+		if v.ReturnType != nil {
+			return v.ReturnType
+		}
+	case *ast.ClassMethod:
+		if heading, ok := v.Heading.(*ast.FunctionHeading); ok && heading.ReturnType != nil {
+			return heading.ReturnType
+		}
+	case *ast.TypeDecl:
+		// Class references such as TFoo of TFoo.Create
+		return ast.NewTypeId(v.Ident, decl)
+	case *ast.Self:
+		if v.TypeDecl != nil {
+			return ast.NewTypeId(v.TypeDecl.Ident, v.TypeDecl.ToDeclarations()[0])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/akm/tparser/ast"
+	"github.com/akm/tparser/token"
+)
+
+func (p *Parser) ParseInterfaceType() (ast.InterfaceType, error) {
+	defer p.TraceMethod("Parser.ParseInterfaceType")()
+
+	t0, err := p.Current(token.Some(
+		token.ReservedWord.HasKeyword("INTERFACE"),
+		token.ReservedWord.HasKeyword("DISPINTERFACE"),
+	))
+	if err != nil {
+		return nil, err
+	}
+	dispInterface := t0.Is(token.ReservedWord.HasKeyword("DISPINTERFACE"))
+	p.NextToken()
+	if p.CurrentToken().Is(token.Symbol(';')) {
+		return &ast.ForwardDeclaredInterfaceType{DispInterface: dispInterface}, nil
+	}
+
+	res := &ast.CustomInterfaceType{DispInterface: dispInterface}
+	if heritage, err := p.ParseInterfaceHeritage(); err != nil {
+		return nil, err
+	} else {
+		res.Heritage = heritage
+	}
+	if guid, err := p.ParseInterfaceGuid(); err != nil {
+		return nil, err
+	} else {
+		res.Guid = guid
+	}
+
+	members := ast.InterfaceMemberList{}
+	if err := p.Until(token.ReservedWord.HasKeyword("END"), nil, func() error {
+		t := p.CurrentToken()
+		switch {
+		case t.Is(token.ReservedWord.HasKeyword("END")):
+			return QuitUntil
+		case t.Is(token.ReservedWord.HasKeyword("PROCEDURE")), t.Is(token.ReservedWord.HasKeyword("FUNCTION")):
+			method, err := p.ParseInterfaceMethod()
+			if err != nil {
+				return err
+			}
+			members = append(members, method)
+		case t.Is(token.ReservedWord.HasKeyword("PROPERTY")):
+			prop, err := p.ParseInterfaceProperty(res)
+			if err != nil {
+				return err
+			}
+			members = append(members, prop)
+		default:
+			return p.TokenErrorf("expects PROCEDURE, FUNCTION, PROPERTY or END but was %s", t)
+		}
+		// Set members one by one so that properties can refer to the methods declared before them.
+		res.Members = members
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		res.Members = nil
+	}
+	return res, nil
+}
+
+func (p *Parser) ParseInterfaceHeritage() (ast.InterfaceHeritage, error) {
+	heritage, err := p.ParseClassHeritage()
+	if err != nil {
+		return nil, err
+	}
+	if heritage == nil {
+		return nil, nil
+	}
+	return ast.InterfaceHeritage(heritage), nil
+}
+
+func (p *Parser) ParseInterfaceGuid() (*ast.InterfaceGuid, error) {
+	if !p.CurrentToken().Is(token.Symbol('[')) {
+		return nil, nil
+	}
+	p.NextToken()
+	expr, err := p.ParseConstExpr()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Current(token.Symbol(']')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+	return &ast.InterfaceGuid{ConstExpr: expr}, nil
+}
+
+func (p *Parser) ParseInterfaceMethod() (*ast.InterfaceMethod, error) {
+	defer p.TraceMethod("Parser.ParseInterfaceMethod")()
+
+	res := &ast.InterfaceMethod{}
+	if err := func() error {
+		defer p.context.StackDeclMap()()
+		heading, err := p.ParseFunctionHeading()
+		if err != nil {
+			return err
+		}
+		res.Heading = heading
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+	if _, err := p.Current(token.Symbol(';')); err != nil {
+		return nil, err
+	}
+	p.NextToken()
+
+	directives := ast.InterfaceMethodDirectives{}
+	for {
+		t := p.CurrentToken()
+		if !t.Is(token.Identifier) || !ast.AllInterfaceMethodDirectives.Include(t.Value()) {
+			break
+		}
+		directive := ast.InterfaceMethodDirective(strings.ToUpper(t.Value()))
+		directives = append(directives, directive)
+		p.NextToken()
+		if directive == ast.ImdDispId {
+			expr, err := p.ParseConstExpr()
+			if err != nil {
+				return nil, err
+			}
+			res.DispId = expr
+		}
+		if _, err := p.Current(token.Symbol(';')); err != nil {
+			return nil, err
+		}
+		p.NextToken()
+	}
+	if len(directives) > 0 {
+		res.Directives = directives
+	}
+	return res, nil
+}
+
+func (p *Parser) ParseInterfaceProperty(intf *ast.CustomInterfaceType) (*ast.InterfaceProperty, error) {
+	defer p.TraceMethod("Parser.ParseInterfaceProperty")()
+
+	if _, err := p.Current(token.ReservedWord.HasKeyword("PROPERTY")); err != nil {
+		return nil, err
+	}
+	t0, err := p.Next(token.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	res := &ast.InterfaceProperty{Ident: p.NewIdent(t0)}
+	p.NextToken()
+
+	if p.CurrentToken().Is(token.Some(token.Symbol(':'), token.Symbol('['))) {
+		propIntf, err := p.ParsePropertyInterface()
+		if err != nil {
+			return nil, err
+		}
+		res.Interface = propIntf
+	}
+
+	accessor := func() (*ast.IdentRef, error) {
+		t := p.NextToken()
+		decl := intf.FindMemberDecl(t.Value())
+		if decl == nil {
+			return nil, p.TokenErrorf("unknown member %s", t)
+		}
+		p.NextToken()
+		return ast.NewIdentRef(p.NewIdent(t), decl), nil
+	}
+
+	for !p.CurrentToken().Is(token.Symbol(';')) {
+		t := p.CurrentToken()
+		switch strings.ToUpper(t.Value()) {
+		case "READ":
+			if res.Read, err = accessor(); err != nil {
+				return nil, err
+			}
+		case "WRITE":
+			if res.Write, err = accessor(); err != nil {
+				return nil, err
+			}
+		case "READONLY":
+			res.ReadOnly = true
+			p.NextToken()
+		case "WRITEONLY":
+			res.WriteOnly = true
+			p.NextToken()
+		case "DISPID":
+			p.NextToken()
+			expr, err := p.ParseConstExpr()
+			if err != nil {
+				return nil, err
+			}
+			res.DispId = expr
+		default:
+			return nil, p.TokenErrorf("unexpected %s in interface property", t)
+		}
+	}
+	p.NextToken()
+
+	if p.CurrentToken().Is(token.UpperCase("DEFAULT")) {
+		res.Default = true
+		if _, err := p.Next(token.Symbol(';')); err != nil {
+			return nil, err
+		}
+		p.NextToken()
+	}
+	return res, nil
+}
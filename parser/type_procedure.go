@@ -9,6 +9,13 @@ func (p *Parser) ParseProcedureType() (*ast.ProcedureType, error) {
 	res := &ast.ProcedureType{}
 
 	t0 := p.CurrentToken()
+	if t0.Is(token.UpperCase("REFERENCE")) {
+		if _, err := p.Next(token.ReservedWord.HasKeyword("TO")); err != nil {
+			return nil, err
+		}
+		res.Reference = true
+		t0 = p.NextToken()
+	}
 	switch t0.Value() {
 	case "FUNCTION":
 		res.FunctionType = ast.FtFunction
@@ -40,7 +47,7 @@ func (p *Parser) ParseProcedureType() (*ast.ProcedureType, error) {
 		res.ReturnType = typ
 	}
 
-	if p.CurrentToken().Is(token.ReservedWord.HasKeyword("OF")) {
+	if !res.Reference && p.CurrentToken().Is(token.ReservedWord.HasKeyword("OF")) {
 		p.NextToken()
 		if _, err := p.Current(token.ReservedWord.HasKeyword("OBJECT")); err != nil {
 			return nil, err
@@ -51,3 +58,14 @@ func (p *Parser) ParseProcedureType() (*ast.ProcedureType, error) {
 
 	return res, nil
 }
+
+// isReferenceTo returns true if the current token is REFERENCE followed by TO.
+// REFERENCE is not a reserved word, so it can be used as an identifier.
+func (p *Parser) isReferenceTo() bool {
+	if !p.CurrentToken().Is(token.UpperCase("REFERENCE")) {
+		return false
+	}
+	rollback := p.RollbackPoint()
+	defer rollback()
+	return p.NextToken().Is(token.ReservedWord.HasKeyword("TO"))
+}
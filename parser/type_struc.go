@@ -133,12 +133,18 @@ func (p *Parser) ParseRecType() (*ast.RecType, error) {
 		return nil, p.TokenErrorf("Expected RECORD, got %s", p.CurrentToken())
 	}
 	p.NextToken()
-	fieldList, err := p.ParseFieldList(token.ReservedWord.HasKeyword("END"))
+	fieldList, err := p.ParseFieldList(p.fieldListBreak())
 	if err != nil {
 		return nil, err
 	}
 	r.FieldList = fieldList
 
+	if !p.CurrentToken().Is(token.ReservedWord.HasKeyword("END")) {
+		if _, err := p.ParseClassMemberSections(r); err != nil {
+			return nil, err
+		}
+	}
+
 	if _, err := p.Current(token.ReservedWord.HasKeyword("END")); err != nil {
 		return nil, err
 	}
@@ -151,7 +157,7 @@ func (p *Parser) ParseFieldList(terminator token.Predicator) (*ast.FieldList, er
 	casePred := token.ReservedWord.HasKeyword("CASE")
 	fieldDecls := ast.FieldDecls{}
 	if err := p.Until(terminator, token.Symbol(';'), func() error {
-		if p.CurrentToken().Is(casePred) || p.CurrentToken().Is(terminator) {
+		if p.CurrentToken().Is(casePred) || p.tokenAfterAttributes().Is(terminator) {
 			return QuitUntil
 		}
 		fieldDecl, err := p.ParseFieldDecl(terminator)
@@ -171,7 +177,7 @@ func (p *Parser) ParseFieldList(terminator token.Predicator) (*ast.FieldList, er
 			return nil, err
 		}
 		r.VariantSection = variantSection
-	} else if !p.CurrentToken().Is(terminator) {
+	} else if !p.tokenAfterAttributes().Is(terminator) {
 		p.NextToken()
 	}
 
@@ -179,11 +185,15 @@ func (p *Parser) ParseFieldList(terminator token.Predicator) (*ast.FieldList, er
 }
 
 func (p *Parser) ParseFieldDecl(terminator token.Predicator) (*ast.FieldDecl, error) {
+	attrs, err := p.ParseAttributes()
+	if err != nil {
+		return nil, err
+	}
 	identList, err := p.ParseIdentList(':')
 	if err != nil {
 		return nil, err
 	}
-	r := &ast.FieldDecl{IdentList: *identList}
+	r := &ast.FieldDecl{Attributes: attrs, IdentList: *identList}
 	p.NextToken()
 	typ, err := p.ParseType()
 	if err != nil {
@@ -1,15 +1,9 @@
 package parser
 
 import (
-	"io/ioutil"
-	"os"
-
 	"github.com/akm/tparser/ast"
 	"github.com/akm/tparser/ast/astcore"
 	"github.com/akm/tparser/token"
-	"github.com/pkg/errors"
-	"golang.org/x/text/encoding/japanese"
-	"golang.org/x/text/transform"
 )
 
 type UnitParser struct {
@@ -23,20 +17,13 @@ func NewUnitParser(ctx *UnitContext) *UnitParser {
 }
 
 func (p *UnitParser) LoadFile() error {
-	fp, err := os.Open(p.context.Path)
-	if err != nil {
-		return errors.Wrapf(err, "failed to open file: %q", p.context.Path)
-	}
-	defer fp.Close()
-
-	decoder := japanese.ShiftJIS.NewDecoder()
-	str, err := ioutil.ReadAll(transform.NewReader(fp, decoder))
+	path := p.context.Path
+	runes, err := readSourceFile(path, p.context.Parent.EncodingFor(path))
 	if err != nil {
 		return err
 	}
-
-	runes := []rune(string(str))
 	p.SetText(&runes)
+	p.setupIncludes(p.context.Parent)
 	p.Parser.NextToken()
 	return nil
 }
@@ -163,6 +150,10 @@ func (p *UnitParser) ParseUnitIntfBody() error {
 		declNodes := decl.GetDeclNodes()
 		for _, declNode := range declNodes {
 			declMap.Set(declNode)
+			if typeDecl, ok := declNode.(*ast.TypeDecl); ok {
+				registerHelper(declMap, typeDecl)
+				registerNestedTypes(declMap, typeDecl)
+			}
 		}
 	}
 	p.Unit.DeclMap = declMap
@@ -170,11 +161,13 @@ func (p *UnitParser) ParseUnitIntfBody() error {
 	return nil
 }
 
-func (m *UnitParser) ProcessImplAndInit() error {
-	if err := m.ParseImplUses(); err != nil {
-		return err
-	}
+func (m *UnitParser) ProcessImplUses() error {
+	return m.ParseImplUses()
+}
 
+// ProcessImplAndInit parses rest of implementation section and initialization section.
+// ProcessImplUses must be called before this method.
+func (m *UnitParser) ProcessImplAndInit() error {
 	m.context.AssignUnits(m.Unit.ImplementationSection.UsesClause)
 	unitsUsedByImpl := m.Unit.ImplementationSection.UsesClause.Units().Compact()
 	// m.context.ImportUnitDecls(m.Unit.ImplementationSection.UsesClause)
@@ -259,6 +252,7 @@ func (p *UnitParser) ParseUnitEnd() error {
 	if _, err := p.Next(token.Symbol('.')); err != nil {
 		return err
 	}
+	p.Unit.CompilerDirectives = p.CompilerDirectives()
 	return nil
 }
 
@@ -9,8 +9,14 @@ func (p *Parser) ParseUsesClause() (ast.UsesClause, error) {
 	if _, err := p.Current(token.ReservedWord.HasKeyword("USES")); err != nil {
 		return nil, err
 	}
-	r := ast.UsesClause{}
 	p.NextToken()
+	return p.parseUsesClauseItems()
+}
+
+// parseUsesClauseItems parses the items of UsesClause or ContainsClause
+// and sets them into the context.
+func (p *Parser) parseUsesClauseItems() (ast.UsesClause, error) {
+	r := ast.UsesClause{}
 	if err := p.Until(token.Symbol(';'), token.Symbol(','), func() error {
 		t, err := p.Current(token.Identifier)
 		if err != nil {
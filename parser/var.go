@@ -16,6 +16,7 @@ func (p *Parser) ParseVarSection(required bool) (ast.VarSection, error) {
 			return nil, nil
 		}
 	}
+	kwToken := p.CurrentToken()
 	p.NextToken()
 	res := ast.VarSection{}
 	for {
@@ -23,6 +24,10 @@ func (p *Parser) ParseVarSection(required bool) (ast.VarSection, error) {
 		if err != nil {
 			return nil, err
 		}
+		if len(res) == 0 {
+			// The compiler directives before VAR are attached to the first declaration.
+			decl.CompilerDirectives = append(p.leadingCompilerDirectives(kwToken), decl.CompilerDirectives...)
+		}
 		{
 			t := p.CurrentToken()
 			if t.Is(token.ReservedWord) || t.Is(token.EOF) {
@@ -43,7 +48,7 @@ func (p *Parser) ParseVarSection(required bool) (ast.VarSection, error) {
 }
 
 func (p *Parser) ParseVarDecl() (*ast.VarDecl, error) {
-	res := &ast.VarDecl{}
+	res := &ast.VarDecl{CompilerDirectives: p.leadingCompilerDirectives(p.CurrentToken())}
 	identList, err := p.ParseIdentList(':')
 	if err != nil {
 		return nil, err
@@ -0,0 +1,45 @@
+package preprocessor
+
+import "strings"
+
+// Constants are the values of identifiers such as CompilerVersion used in {$IF}.
+// Names are case-insensitive.
+type Constants map[string]float64
+
+// DefaultConstants returns the constants of Delphi 11 Alexandria.
+func DefaultConstants() Constants {
+	return Constants{
+		"COMPILERVERSION": 35.0,
+		"RTLVERSION":      35.0,
+	}
+}
+
+func (m Constants) Set(name string, value float64) {
+	m[strings.ToUpper(name)] = value
+}
+
+func (m Constants) Get(name string) (float64, bool) {
+	if v, ok := m[strings.ToUpper(name)]; ok {
+		return v, true
+	}
+	// The names of Constants given as a literal may not be upper case.
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// Merge returns a copy of m overwritten by the constants of other.
+// The names of other can be in any case.
+func (m Constants) Merge(other Constants) Constants {
+	r := make(Constants, len(m)+len(other))
+	for k, v := range m {
+		r.Set(k, v)
+	}
+	for k, v := range other {
+		r.Set(k, v)
+	}
+	return r
+}
@@ -0,0 +1,35 @@
+package preprocessor
+
+import "strings"
+
+// Defines is a set of conditional symbols defined by {$DEFINE} or compiler options.
+// Conditional symbols are case-insensitive.
+type Defines map[string]bool
+
+func NewDefines(names ...string) Defines {
+	r := Defines{}
+	for _, name := range names {
+		r.Define(name)
+	}
+	return r
+}
+
+func (m Defines) Define(name string) {
+	m[strings.ToUpper(name)] = true
+}
+
+func (m Defines) Undefine(name string) {
+	delete(m, strings.ToUpper(name))
+}
+
+func (m Defines) IsDefined(name string) bool {
+	return m[strings.ToUpper(name)]
+}
+
+func (m Defines) Clone() Defines {
+	r := make(Defines, len(m))
+	for k, v := range m {
+		r[k] = v
+	}
+	return r
+}
@@ -0,0 +1,37 @@
+package preprocessor
+
+import (
+	"strings"
+
+	"github.com/akm/tparser/token"
+)
+
+// Directive is a compiler directive such as `{$IFDEF DEBUG}` or `(*$R+*)`.
+type Directive struct {
+	Name     string // upper case name such as "IFDEF" or "R"
+	Argument string // the rest of the directive such as "DEBUG" or "+"
+	Token    *token.Token
+}
+
+// ParseDirective returns the directive in the CompilerDirective token.
+// It returns nil if t is not a compiler directive.
+func ParseDirective(t *token.Token) *Directive {
+	if t.Type != token.CompilerDirective || t.CompilerDirective == nil {
+		return nil
+	}
+	return &Directive{
+		Name:     t.CompilerDirective.Name,
+		Argument: t.CompilerDirective.Argument,
+		Token:    t,
+	}
+}
+
+// FirstArgument returns the first word of Argument.
+// Conditional directives such as `{$IFDEF DEBUG comment}` ignore the rest.
+func (d *Directive) FirstArgument() string {
+	fields := strings.Fields(d.Argument)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
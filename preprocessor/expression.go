@@ -0,0 +1,236 @@
+package preprocessor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/akm/tparser/token"
+	"github.com/pkg/errors"
+)
+
+// evaluator evaluates the expression of {$IF} and {$ELSEIF}.
+//
+//   ```
+//   Expression := SimpleExpression [RelOp SimpleExpression]
+//   SimpleExpression := Term [(OR | XOR) Term]...
+//   Term := Factor [AND Factor]...
+//   Factor := NOT Factor | '(' Expression ')' | DEFINED '(' Ident ')' | DECLARED '(' Ident ')'
+//           | TRUE | FALSE | Number | Ident
+//   ```
+type evaluator struct {
+	tokenizer    *token.Tokenizer
+	curr         *token.Token
+	preprocessor *Preprocessor
+}
+
+func (p *Preprocessor) evaluate(expr string) (bool, error) {
+	text := []rune(expr)
+	e := &evaluator{tokenizer: token.NewTokenizer(&text, 0), preprocessor: p}
+	e.next()
+	v, err := e.expression()
+	if err != nil {
+		return false, err
+	}
+	if e.curr.Type != token.EOF {
+		return false, errors.Errorf("unexpected %s in %q", e.curr.RawString(), expr)
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.Errorf("%q is not a boolean expression", expr)
+	}
+	return b, nil
+}
+
+func (e *evaluator) next() *token.Token {
+	e.curr = e.tokenizer.GetNext()
+	return e.curr
+}
+
+func (e *evaluator) expression() (interface{}, error) {
+	left, err := e.simpleExpression()
+	if err != nil {
+		return nil, err
+	}
+	switch op := e.curr.RawString(); op {
+	case "=", "<>", "<", ">", "<=", ">=":
+		e.next()
+		right, err := e.simpleExpression()
+		if err != nil {
+			return nil, err
+		}
+		return compare(op, left, right)
+	}
+	return left, nil
+}
+
+func (e *evaluator) simpleExpression() (interface{}, error) {
+	left, err := e.term()
+	if err != nil {
+		return nil, err
+	}
+	for e.curr.Is(token.ReservedWord.HasKeyword("OR")) || e.curr.Is(token.ReservedWord.HasKeyword("XOR")) {
+		op := e.curr.Value()
+		e.next()
+		right, err := e.term()
+		if err != nil {
+			return nil, err
+		}
+		l, r, err := booleans(left, right)
+		if err != nil {
+			return nil, err
+		}
+		if op == "OR" {
+			left = l || r
+		} else {
+			left = l != r
+		}
+	}
+	return left, nil
+}
+
+func (e *evaluator) term() (interface{}, error) {
+	left, err := e.factor()
+	if err != nil {
+		return nil, err
+	}
+	for e.curr.Is(token.ReservedWord.HasKeyword("AND")) {
+		e.next()
+		right, err := e.factor()
+		if err != nil {
+			return nil, err
+		}
+		l, r, err := booleans(left, right)
+		if err != nil {
+			return nil, err
+		}
+		left = l && r
+	}
+	return left, nil
+}
+
+func (e *evaluator) factor() (interface{}, error) {
+	t := e.curr
+	switch {
+	case t.Is(token.ReservedWord.HasKeyword("NOT")):
+		e.next()
+		v, err := e.factor()
+		if err != nil {
+			return nil, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return nil, errors.Errorf("NOT requires a boolean value")
+		}
+		return !b, nil
+	case t.Is(token.Symbol('(')):
+		e.next()
+		v, err := e.expression()
+		if err != nil {
+			return nil, err
+		}
+		if !e.curr.Is(token.Symbol(')')) {
+			return nil, errors.Errorf("expects ) but was %s", e.curr.RawString())
+		}
+		e.next()
+		return v, nil
+	case t.Type == token.NumeralInt || t.Type == token.NumeralReal:
+		e.next()
+		v, err := strconv.ParseFloat(t.RawString(), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid number %s", t.RawString())
+		}
+		return v, nil
+	case t.Type == token.Identifier:
+		name := t.RawString()
+		e.next()
+		switch strings.ToUpper(name) {
+		case "DEFINED", "DECLARED":
+			arg, err := e.functionArgument(name)
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(name, "DEFINED") {
+				return e.preprocessor.defines.IsDefined(arg), nil
+			}
+			return e.preprocessor.Declared != nil && e.preprocessor.Declared(arg), nil
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		}
+		if v, ok := e.preprocessor.Constants.Get(name); ok {
+			return v, nil
+		}
+		return nil, errors.Errorf("undeclared identifier %s", name)
+	default:
+		return nil, errors.Errorf("unexpected %s", t.RawString())
+	}
+}
+
+func (e *evaluator) functionArgument(name string) (string, error) {
+	if !e.curr.Is(token.Symbol('(')) {
+		return "", errors.Errorf("expects ( after %s", name)
+	}
+	// The argument can be a qualified identifier such as System.TObject.
+	parts := []string{}
+	for {
+		arg := e.next()
+		if arg.Type != token.Identifier && arg.Type != token.ReservedWord {
+			return "", errors.Errorf("expects identifier for %s but was %s", name, arg.RawString())
+		}
+		parts = append(parts, arg.RawString())
+		if !e.next().Is(token.Symbol('.')) {
+			break
+		}
+	}
+	if !e.curr.Is(token.Symbol(')')) {
+		return "", errors.Errorf("expects ) for %s but was %s", name, e.curr.RawString())
+	}
+	e.next()
+	return strings.Join(parts, "."), nil
+}
+
+func booleans(left, right interface{}) (bool, bool, error) {
+	l, ok1 := left.(bool)
+	r, ok2 := right.(bool)
+	if !ok1 || !ok2 {
+		return false, false, errors.Errorf("boolean operator requires boolean values")
+	}
+	return l, r, nil
+}
+
+func compare(op string, left, right interface{}) (bool, error) {
+	if l, ok := left.(bool); ok {
+		r, ok := right.(bool)
+		if !ok {
+			return false, errors.Errorf("cannot compare boolean and number")
+		}
+		switch op {
+		case "=":
+			return l == r, nil
+		case "<>":
+			return l != r, nil
+		default:
+			return false, errors.Errorf("%s is not supported for boolean values", op)
+		}
+	}
+	l, ok1 := left.(float64)
+	r, ok2 := right.(float64)
+	if !ok1 || !ok2 {
+		return false, errors.Errorf("cannot compare boolean and number")
+	}
+	switch op {
+	case "=":
+		return l == r, nil
+	case "<>":
+		return l != r, nil
+	case "<":
+		return l < r, nil
+	case ">":
+		return l > r, nil
+	case "<=":
+		return l <= r, nil
+	default: // ">="
+		return l >= r, nil
+	}
+}
@@ -0,0 +1,148 @@
+package preprocessor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akm/tparser/token"
+	"github.com/pkg/errors"
+)
+
+// IncludeSearchPath is an ordered list of directories where files included by
+// {$I file} or {$INCLUDE file} are looked up when they are not found in the
+// directory of the including file.
+type IncludeSearchPath []string
+
+// Find returns the path of fileName in the first directory which contains it.
+// File names are compared case-insensitively like Delphi on Windows does.
+func (s IncludeSearchPath) Find(fileName string) (string, error) {
+	if filepath.IsAbs(fileName) {
+		if _, err := os.Stat(fileName); err != nil {
+			return "", &IncludeFileNotFoundError{FileName: fileName}
+		}
+		return fileName, nil
+	}
+	for _, dir := range s {
+		path := filepath.Join(dir, fileName)
+		entries, err := os.ReadDir(filepath.Dir(path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		base := strings.ToLower(filepath.Base(path))
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.ToLower(entry.Name()) == base {
+				return filepath.Join(filepath.Dir(path), entry.Name()), nil
+			}
+		}
+	}
+	return "", &IncludeFileNotFoundError{FileName: fileName, SearchedDirs: s}
+}
+
+// IncludeFileNotFoundError is returned when an include file is not found.
+type IncludeFileNotFoundError struct {
+	FileName     string
+	SearchedDirs []string
+}
+
+func (e *IncludeFileNotFoundError) Error() string {
+	return fmt.Sprintf("include file %s not found in %s", e.FileName, strings.Join(e.SearchedDirs, ", "))
+}
+
+// include is a file being read by the preprocessor.
+type include struct {
+	path      string
+	tokenizer *token.Tokenizer
+	trivia    token.Trivia // dropped tokens for the leading trivia of the next token in the file
+
+	last         *token.Token // the last token returned from the file
+	lastTrailing token.Trivia // the trailing trivia of last read by the tokenizer
+}
+
+func (i *include) clone() *include {
+	return &include{
+		path:         i.path,
+		tokenizer:    i.tokenizer.Clone(),
+		trivia:       append(token.Trivia(nil), i.trivia...),
+		last:         i.last,
+		lastTrailing: i.lastTrailing,
+	}
+}
+
+func (i *include) setLast(t *token.Token) {
+	i.last = t
+	i.lastTrailing = t.TrailingTrivia
+}
+
+// includeFileName returns the file name of {$I file} or {$INCLUDE file}.
+// It returns "" for the switch directives {$I+} and {$I-}.
+func includeFileName(d *Directive) string {
+	arg := d.Argument
+	if d.Name == "I" && (arg == "+" || arg == "-" || strings.HasPrefix(arg, "+,") || strings.HasPrefix(arg, "-,")) {
+		return ""
+	}
+	if strings.HasPrefix(arg, "'") {
+		if end := strings.Index(arg[1:], "'"); end >= 0 {
+			return arg[1 : end+1]
+		}
+	}
+	return d.FirstArgument()
+}
+
+// processInclude pushes the tokenizer for the included file.
+func (p *Preprocessor) processInclude(fileName string) error {
+	current := p.currentPath()
+	dirs := IncludeSearchPath{filepath.Dir(current)}
+	if current == "" {
+		dirs = IncludeSearchPath{"."}
+	}
+	dirs = append(dirs, p.IncludeSearchPath...)
+	path, err := dirs.Find(fileName)
+	if err != nil {
+		return err
+	}
+
+	cleaned := filepath.Clean(path)
+	if p.Path != "" && filepath.Clean(p.Path) == cleaned {
+		return errors.Errorf("recursive include of %s", path)
+	}
+	for _, i := range p.includes {
+		if filepath.Clean(i.path) == cleaned {
+			return errors.Errorf("recursive include of %s", path)
+		}
+	}
+
+	readFile := p.ReadFile
+	if readFile == nil {
+		readFile = readUTF8File
+	}
+	text, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	tokenizer := token.NewTokenizer(&text, p.tokenizer.Flags()|token.LoadComment)
+	tokenizer.Position.Path = path
+	p.includes = append(p.includes, &include{path: path, tokenizer: tokenizer})
+	return nil
+}
+
+// currentPath returns the path of the file which is being read.
+func (p *Preprocessor) currentPath() string {
+	if len(p.includes) > 0 {
+		return p.includes[len(p.includes)-1].path
+	}
+	return p.Path
+}
+
+func readUTF8File(path string) ([]rune, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open file: %q", path)
+	}
+	return []rune(string(b)), nil
+}
@@ -0,0 +1,423 @@
+package preprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akm/tparser/log"
+	"github.com/akm/tparser/token"
+	"github.com/pkg/errors"
+)
+
+// Preprocessor reads tokens from Tokenizer and evaluates conditional compilation
+// directives. It returns only tokens in active blocks, and drops spaces, comments
+// and compiler directives. Files included by {$I file} or {$INCLUDE file} are
+// read in place of the directives. The compiler directives which are not in
+// inactive blocks are kept as Directives, and are also put to the
+// LeadingDirectives of the next token.
+//
+// If Tokenizer is created with token.LoadTrivia, the dropped compiler directives
+// and the tokens in inactive blocks are put to the leading trivia of the next
+// token in the same file, so that the tokens can reproduce the original text.
+// The tokens read from include files have the paths of the files in their
+// positions and are marked as Included. The trivia at the end of an include
+// file are put to the trailing trivia of its last token.
+type Preprocessor struct {
+	tokenizer  *token.Tokenizer
+	includes   []*include
+	defines    Defines
+	switches   map[string]bool
+	conditions []*condition
+	asmMode    bool
+	trivia     token.Trivia // dropped tokens for the leading trivia of the next token in the text
+	directives []*Directive
+	leading    []*token.Token // directive tokens for the LeadingDirectives of the next token
+	err        error
+
+	// Path is the path of the text given as Tokenizer. Include files are looked up
+	// in the directory of the including file first.
+	Path string
+	// IncludeSearchPath is used when include files are not found in the directory of the including file.
+	IncludeSearchPath IncludeSearchPath
+	// ReadFile reads include files. Files are read as UTF-8 if it is nil.
+	ReadFile func(path string) ([]rune, error)
+
+	// Declared returns true if the identifier is declared. It is used by Declared() in {$IF}.
+	Declared func(name string) bool
+	// Constants are the values of identifiers such as CompilerVersion in {$IF}.
+	// NewPreprocessor sets DefaultConstants.
+	Constants Constants
+}
+
+// condition is the state of a conditional block from {$IFxxx} to {$ENDIF}.
+type condition struct {
+	parentActive bool // the enclosing block is active
+	active       bool // the current branch is active
+	taken        bool // one of the branches has been taken
+}
+
+// defaultSwitches are the switch directives which are ON by default.
+var defaultSwitches = []string{"C", "D", "H", "I", "L", "O", "X"}
+
+// longSwitchNames maps long switch directive names to single letter ones.
+var longSwitchNames = map[string]string{
+	"ASSERTIONS":     "C",
+	"BOOLEVAL":       "B",
+	"DEBUGINFO":      "D",
+	"EXTENDEDSYNTAX": "X",
+	"IOCHECKS":       "I",
+	"LONGSTRINGS":    "H",
+	"LOCALSYMBOLS":   "L",
+	"OPTIMIZATION":   "O",
+	"OVERFLOWCHECKS": "Q",
+	"RANGECHECKS":    "R",
+	"TYPEDADDRESS":   "T",
+	"WRITEABLECONST": "J",
+}
+
+func NewPreprocessor(tokenizer *token.Tokenizer, defines Defines) *Preprocessor {
+	if defines == nil {
+		defines = Defines{}
+	}
+	switches := map[string]bool{}
+	for _, s := range defaultSwitches {
+		switches[s] = true
+	}
+	return &Preprocessor{
+		tokenizer: tokenizer,
+		defines:   defines.Clone(),
+		switches:  switches,
+		Constants: DefaultConstants(),
+	}
+}
+
+func (p *Preprocessor) Clone() *Preprocessor {
+	switches := make(map[string]bool, len(p.switches))
+	for k, v := range p.switches {
+		switches[k] = v
+	}
+	conditions := make([]*condition, len(p.conditions))
+	for i, c := range p.conditions {
+		v := *c
+		conditions[i] = &v
+	}
+	includes := make([]*include, len(p.includes))
+	for i, inc := range p.includes {
+		includes[i] = inc.clone()
+	}
+	return &Preprocessor{
+		tokenizer:         p.tokenizer.Clone(),
+		includes:          includes,
+		defines:           p.defines.Clone(),
+		switches:          switches,
+		conditions:        conditions,
+		asmMode:           p.asmMode,
+		trivia:            append(token.Trivia(nil), p.trivia...),
+		directives:        append([]*Directive(nil), p.directives...),
+		leading:           append([]*token.Token(nil), p.leading...),
+		err:               p.err,
+		Path:              p.Path,
+		IncludeSearchPath: p.IncludeSearchPath,
+		ReadFile:          p.ReadFile,
+		Declared:          p.Declared,
+		Constants:         p.Constants,
+	}
+}
+
+// Defines returns the conditional symbols defined at the current position.
+func (p *Preprocessor) Defines() Defines {
+	return p.defines
+}
+
+// Directives returns the compiler directives read so far in the order of
+// their positions. The directives in inactive blocks are not included, but
+// the conditional directives which switch the blocks are included.
+func (p *Preprocessor) Directives() []*Directive {
+	return p.directives
+}
+
+// Err returns the first error of the expressions in {$IF} and {$ELSEIF}, or
+// of the files included by {$I file}. The block of the expression is treated
+// as inactive and the file which can't be included is skipped, so the tokens
+// read after the error may not be the ones which the compiler reads.
+func (p *Preprocessor) Err() error {
+	return p.err
+}
+
+// SetAsmMode switches the tokenizers of the text and include files
+// to read the tokens in asm blocks or not.
+func (p *Preprocessor) SetAsmMode(v bool) {
+	p.asmMode = v
+}
+
+func (p *Preprocessor) GetNext() *token.Token {
+	for {
+		tokenizer := p.currentTokenizer()
+		tokenizer.SetAsmMode(p.asmMode)
+		t := tokenizer.GetNext()
+		if (t == nil || t.Type == token.EOF) && len(p.includes) > 0 {
+			if t != nil {
+				p.keepTrivia(t.LeadingTrivia...)
+			}
+			p.popInclude()
+			continue
+		}
+		if t == nil || t.Type == token.EOF {
+			if len(p.conditions) > 0 {
+				log.Printf("%d conditional directive(s) are not terminated", len(p.conditions))
+			}
+			if t != nil {
+				p.attachTrivia(t)
+				p.attachDirectives(t)
+			}
+			return t
+		}
+		switch t.Type {
+		case token.Space:
+			continue
+		case token.Comment:
+			p.keepTrivia(t.Flatten()...)
+			continue
+		case token.CompilerDirective:
+			// The directive is kept before it is processed, because {$I file}
+			// switches the file whose trivia are kept.
+			p.keepTrivia(t.Flatten()...)
+			if d := ParseDirective(t); d != nil {
+				wasActive := p.active()
+				p.processDirective(d)
+				if wasActive || p.active() {
+					p.directives = append(p.directives, d)
+					p.leading = append(p.leading, t)
+				}
+			}
+			continue
+		}
+		if p.active() {
+			p.attachTrivia(t)
+			p.attachDirectives(t)
+			if len(p.includes) > 0 {
+				t.Included = true
+				p.includes[len(p.includes)-1].setLast(t)
+			}
+			return t
+		}
+		p.keepTrivia(t.Flatten()...)
+	}
+}
+
+// popInclude finishes reading the current include file.
+// The trivia at the end of the file are put to the trailing trivia of the
+// last token of the file, because they are not a part of the including file.
+// They are dropped if the file has no token.
+func (p *Preprocessor) popInclude() {
+	inc := p.includes[len(p.includes)-1]
+	p.includes = p.includes[:len(p.includes)-1]
+	if inc.last != nil && len(inc.trivia) > 0 {
+		// The original trailing trivia are used because the last token is
+		// shared with the clones of Preprocessor which may pop the file again.
+		trailing := append(token.Trivia{}, inc.lastTrailing...)
+		inc.last.TrailingTrivia = append(trailing, inc.trivia...)
+	}
+}
+
+func (p *Preprocessor) lossless() bool {
+	return p.tokenizer.Flags()&token.LoadTrivia != 0
+}
+
+// pendingTrivia returns the kept tokens of the file which is being read.
+func (p *Preprocessor) pendingTrivia() *token.Trivia {
+	if len(p.includes) > 0 {
+		return &p.includes[len(p.includes)-1].trivia
+	}
+	return &p.trivia
+}
+
+// keepTrivia keeps the dropped tokens for the leading trivia of the next token.
+func (p *Preprocessor) keepTrivia(tokens ...*token.Token) {
+	if p.lossless() {
+		trivia := p.pendingTrivia()
+		*trivia = append(*trivia, tokens...)
+	}
+}
+
+// attachTrivia puts the kept tokens to the leading trivia of t.
+func (p *Preprocessor) attachTrivia(t *token.Token) {
+	trivia := p.pendingTrivia()
+	if len(*trivia) == 0 {
+		return
+	}
+	t.LeadingTrivia = append(*trivia, t.LeadingTrivia...)
+	*trivia = nil
+}
+
+// attachDirectives puts the directives read since the last token to t.
+func (p *Preprocessor) attachDirectives(t *token.Token) {
+	if len(p.leading) == 0 {
+		return
+	}
+	t.LeadingDirectives = p.leading
+	p.leading = nil
+}
+
+func (p *Preprocessor) currentTokenizer() *token.Tokenizer {
+	if len(p.includes) > 0 {
+		return p.includes[len(p.includes)-1].tokenizer
+	}
+	return p.tokenizer
+}
+
+func (p *Preprocessor) active() bool {
+	if len(p.conditions) == 0 {
+		return true
+	}
+	return p.conditions[len(p.conditions)-1].active
+}
+
+func (p *Preprocessor) processDirective(d *Directive) {
+	switch d.Name {
+	case "IFDEF":
+		p.pushCondition(func() (bool, error) { return p.defines.IsDefined(d.FirstArgument()), nil })
+	case "IFNDEF":
+		p.pushCondition(func() (bool, error) { return !p.defines.IsDefined(d.FirstArgument()), nil })
+	case "IF":
+		p.pushCondition(func() (bool, error) { return p.evaluateDirective(d) })
+	case "IFOPT":
+		p.pushCondition(func() (bool, error) { return p.evaluateOption(d.FirstArgument()), nil })
+	case "ELSEIF":
+		c := p.topCondition(d)
+		if c == nil {
+			return
+		}
+		if c.taken || !c.parentActive {
+			c.active = false
+			return
+		}
+		v, err := p.evaluateDirective(d)
+		if err != nil {
+			p.setError(err)
+		}
+		c.active = v
+		c.taken = v
+	case "ELSE":
+		c := p.topCondition(d)
+		if c == nil {
+			return
+		}
+		c.active = c.parentActive && !c.taken
+		c.taken = true
+	case "ENDIF", "IFEND":
+		if p.topCondition(d) == nil {
+			return
+		}
+		p.conditions = p.conditions[:len(p.conditions)-1]
+	default:
+		if !p.active() {
+			return
+		}
+		switch d.Name {
+		case "DEFINE":
+			p.defines.Define(d.FirstArgument())
+		case "UNDEF":
+			p.defines.Undefine(d.FirstArgument())
+		case "I", "INCLUDE":
+			if fileName := includeFileName(d); fileName != "" {
+				if err := p.processInclude(fileName); err != nil {
+					p.setError(errors.Wrapf(err, "failed to include %s at %s", fileName, placeString(d.Token)))
+				}
+				return
+			}
+			p.processSwitches(d)
+		default:
+			p.processSwitches(d)
+		}
+	}
+}
+
+func (p *Preprocessor) pushCondition(evaluate func() (bool, error)) {
+	parentActive := p.active()
+	c := &condition{parentActive: parentActive}
+	if parentActive {
+		v, err := evaluate()
+		if err != nil {
+			p.setError(err)
+		}
+		c.active = v
+		c.taken = v
+	}
+	p.conditions = append(p.conditions, c)
+}
+
+func (p *Preprocessor) topCondition(d *Directive) *condition {
+	if len(p.conditions) == 0 {
+		log.Printf("%s without $IF at %s", d.Token.RawString(), placeString(d.Token))
+		return nil
+	}
+	return p.conditions[len(p.conditions)-1]
+}
+
+// evaluateDirective evaluates the expression of {$IF} or {$ELSEIF}.
+func (p *Preprocessor) evaluateDirective(d *Directive) (bool, error) {
+	v, err := p.evaluate(d.Argument)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to evaluate %s at %s", d.Token.RawString(), placeString(d.Token))
+	}
+	return v, nil
+}
+
+func (p *Preprocessor) setError(err error) {
+	log.Printf("%v", err)
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// evaluateOption returns the result of {$IFOPT X+} or {$IFOPT X-}.
+func (p *Preprocessor) evaluateOption(arg string) bool {
+	if len(arg) < 2 {
+		return false
+	}
+	name, state := strings.ToUpper(arg[:len(arg)-1]), arg[len(arg)-1]
+	switch state {
+	case '+':
+		return p.switches[name]
+	case '-':
+		return !p.switches[name]
+	default:
+		return false
+	}
+}
+
+// processSwitches processes switch directives such as {$R+}, {$R+,Q-} or {$RANGECHECKS ON}.
+func (p *Preprocessor) processSwitches(d *Directive) {
+	if letter, ok := longSwitchNames[d.Name]; ok {
+		switch strings.ToUpper(d.FirstArgument()) {
+		case "ON":
+			p.switches[letter] = true
+		case "OFF":
+			p.switches[letter] = false
+		}
+		return
+	}
+	if len(d.Name) != 1 {
+		return
+	}
+	for _, item := range strings.Split(d.Name+d.Argument, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) != 2 {
+			continue
+		}
+		switch item[1] {
+		case '+':
+			p.switches[strings.ToUpper(item[:1])] = true
+		case '-':
+			p.switches[strings.ToUpper(item[:1])] = false
+		}
+	}
+}
+
+func placeString(t *token.Token) string {
+	if t.Start.Path != "" {
+		return fmt.Sprintf("%s:%d:%d", t.Start.Path, t.Start.Line, t.Start.Col)
+	}
+	return fmt.Sprintf("%d:%d", t.Start.Line, t.Start.Col)
+}
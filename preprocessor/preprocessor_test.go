@@ -0,0 +1,364 @@
+package preprocessor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func preprocess(text string, defines Defines, setup func(*Preprocessor)) []string {
+	runes := []rune(text)
+	p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadSpace|token.LoadComment), defines)
+	if setup != nil {
+		setup(p)
+	}
+	return tokenValues(p)
+}
+
+func TestPreprocessor(t *testing.T) {
+	type pattern struct {
+		name     string
+		text     string
+		defines  Defines
+		expected string
+	}
+
+	patterns := []pattern{
+		{"no directive", "a { comment } b // comment\n c (* comment *)", nil, "a b c"},
+		{"IFDEF defined", "a {$IFDEF DEBUG} b {$ENDIF} c", NewDefines("debug"), "a b c"},
+		{"IFDEF undefined", "a {$IFDEF DEBUG} b {$ENDIF} c", nil, "a c"},
+		{"IFNDEF", "a {$IFNDEF DEBUG} b {$ELSE} c {$ENDIF} d", nil, "a b d"},
+		{"ELSE", "a {$IFDEF DEBUG} b {$ELSE} c {$ENDIF} d", nil, "a c d"},
+		{"paren star", "a (*$IFDEF DEBUG*) b (*$ELSE*) c (*$ENDIF*) d", NewDefines("DEBUG"), "a b d"},
+		{
+			"nested",
+			"a {$IFDEF X} b {$IFDEF Y} c {$ELSE} d {$ENDIF} e {$ELSE} f {$IFDEF Y} g {$ENDIF} {$ENDIF} h",
+			NewDefines("X"),
+			"a b d e h",
+		},
+		{
+			"nested in inactive block",
+			"a {$IFDEF X} b {$IFNDEF Y} c {$ELSE} d {$ENDIF} {$ENDIF} e",
+			nil,
+			"a e",
+		},
+		{"DEFINE", "{$DEFINE FOO} {$IFDEF FOO} a {$ENDIF} b", nil, "a b"},
+		{"UNDEF", "{$UNDEF FOO} {$IFDEF FOO} a {$ENDIF} b", NewDefines("FOO"), "b"},
+		{"DEFINE in inactive block", "{$IFDEF X} {$DEFINE FOO} {$ENDIF} {$IFDEF FOO} a {$ENDIF} b", nil, "b"},
+		{"IF Defined", "{$IF Defined(X) and not Defined(Y)} a {$ELSE} b {$IFEND}", NewDefines("X"), "a"},
+		{"IF or", "{$IF Defined(X) or Defined(Y)} a {$ELSE} b {$ENDIF}", nil, "b"},
+		{
+			"ELSEIF",
+			"{$IF Defined(X)} a {$ELSEIF Defined(Y)} b {$ELSEIF Defined(Z)} c {$ELSE} d {$IFEND}",
+			NewDefines("Y", "Z"),
+			"b",
+		},
+		{"IF CompilerVersion", "{$IF CompilerVersion >= 20.0} a {$ELSE} b {$IFEND}", nil, "a"},
+		{"IF Declared", "{$IF Declared(Foo)} a {$ELSE} b {$IFEND} {$IF Declared(Bar)} c {$IFEND}", nil, "a"},
+		{"IF Declared qualified", "{$IF Declared(System.Bar)} a {$ELSE} b {$IFEND}", nil, "b"},
+		{"IFOPT default", "{$IFOPT R+} a {$ELSE} b {$ENDIF} {$IFOPT C+} c {$ENDIF}", nil, "b c"},
+		{"IFOPT switched", "{$R+,C-} {$IFOPT R+} a {$ENDIF} {$IFOPT C-} b {$ENDIF}", nil, "a b"},
+		{"IFOPT long name", "{$RANGECHECKS ON} {$IFOPT R+} a {$ENDIF}", nil, "a"},
+	}
+
+	for _, ptn := range patterns {
+		t.Run(ptn.name, func(t *testing.T) {
+			actual := preprocess(ptn.text, ptn.defines, func(p *Preprocessor) {
+				p.Declared = func(name string) bool { return strings.EqualFold(name, "foo") }
+				p.Constants = Constants{"CompilerVersion": 21.0}
+			})
+			assert.Equal(t, strings.Fields(ptn.expected), actual)
+		})
+	}
+}
+
+func TestPreprocessorDefaultConstants(t *testing.T) {
+	actual := preprocess("{$IF CompilerVersion >= 20} a {$IFEND} {$IF RTLVersion < 20} b {$IFEND}", nil, nil)
+	assert.Equal(t, []string{"a"}, actual)
+
+	constants := DefaultConstants().Merge(Constants{"CompilerVersion": 15, "FireMonkeyVersion": 27})
+	v, ok := constants.Get("COMPILERVERSION")
+	assert.True(t, ok)
+	assert.Equal(t, 15.0, v)
+	v, ok = constants.Get("RTLVersion")
+	assert.True(t, ok)
+	assert.Equal(t, 35.0, v)
+	_, ok = constants.Get("firemonkeyversion")
+	assert.True(t, ok)
+}
+
+func TestPreprocessorDoesNotChangeGivenDefines(t *testing.T) {
+	defines := NewDefines("FOO")
+	preprocess("{$UNDEF FOO} {$DEFINE BAR}", defines, nil)
+	assert.Equal(t, NewDefines("FOO"), defines)
+}
+
+func TestPreprocessorClone(t *testing.T) {
+	runes := []rune("{$IFDEF X} a {$ELSE} b {$ENDIF} c")
+	p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment), nil)
+	assert.Equal(t, "b", p.GetNext().RawString())
+	clone := p.Clone()
+	assert.Equal(t, "c", p.GetNext().RawString())
+	assert.Equal(t, "c", clone.GetNext().RawString())
+}
+
+func TestPreprocessorEvaluationError(t *testing.T) {
+	patterns := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"IF", "{$IF SizeOf(Pointer) = 8} a {$ELSE} b {$IFEND}", "undeclared identifier SizeOf"},
+		{"ELSEIF", "{$IF Defined(X)} a {$ELSEIF Foo > 1} b {$IFEND}", "undeclared identifier Foo"},
+	}
+	for _, ptn := range patterns {
+		t.Run(ptn.name, func(t *testing.T) {
+			runes := []rune(ptn.text)
+			p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment), nil)
+			assert.NoError(t, p.Err())
+			tokenValues(p)
+			if assert.Error(t, p.Err()) {
+				assert.Contains(t, p.Err().Error(), ptn.expected)
+				assert.Contains(t, p.Err().Error(), "at 1:")
+			}
+		})
+	}
+
+	t.Run("in inactive block", func(t *testing.T) {
+		runes := []rune("{$IFDEF X} {$IF Foo > 1} a {$IFEND} {$ENDIF} b")
+		p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment), nil)
+		assert.Equal(t, []string{"b"}, tokenValues(p))
+		assert.NoError(t, p.Err())
+	})
+}
+
+func TestParseDirective(t *testing.T) {
+	runes := []rune("{$IFDEF DEBUG comment} (*$R+*) { comment } {$ifend}")
+	tokenizer := token.NewTokenizer(&runes, token.LoadComment)
+
+	d := ParseDirective(tokenizer.GetNext())
+	if assert.NotNil(t, d) {
+		assert.Equal(t, "IFDEF", d.Name)
+		assert.Equal(t, "DEBUG comment", d.Argument)
+		assert.Equal(t, "DEBUG", d.FirstArgument())
+	}
+	d = ParseDirective(tokenizer.GetNext())
+	if assert.NotNil(t, d) {
+		assert.Equal(t, "R", d.Name)
+		assert.Equal(t, "+", d.Argument)
+	}
+	assert.Nil(t, ParseDirective(tokenizer.GetNext()))
+	d = ParseDirective(tokenizer.GetNext())
+	if assert.NotNil(t, d) {
+		assert.Equal(t, "IFEND", d.Name)
+		assert.Equal(t, "", d.Argument)
+	}
+}
+
+func TestPreprocessorInclude(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.pas":     "a {$I sub\\b.inc} {$INCLUDE 'C.INC'} {$I+} {$IFDEF FROM_C} f {$ENDIF}",
+		"sub/b.inc":    "b1\nb2 {$I d.inc}",
+		"sub/d.inc":    "d",
+		"c.inc":        "c {$DEFINE FROM_C}",
+		"self.inc":     "s {$I self.inc}",
+		"common/e.inc": "e",
+	}
+	files["main.pas"] = strings.Replace(files["main.pas"], "\\", string(filepath.Separator), 1)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755)) {
+			return
+		}
+		if !assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644)) {
+			return
+		}
+	}
+
+	newPreprocessor := func(text string) *Preprocessor {
+		runes := []rune(text)
+		p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment), nil)
+		p.Path = filepath.Join(dir, "main.pas")
+		p.IncludeSearchPath = IncludeSearchPath{filepath.Join(dir, "common")}
+		return p
+	}
+
+	t.Run("splice included files", func(t *testing.T) {
+		p := newPreprocessor(files["main.pas"])
+		tokens := []*token.Token{}
+		for {
+			tk := p.GetNext()
+			if tk.Type == token.EOF {
+				break
+			}
+			tokens = append(tokens, tk)
+		}
+		values := []string{}
+		for _, tk := range tokens {
+			values = append(values, tk.RawString())
+		}
+		assert.Equal(t, []string{"a", "b1", "b2", "d", "c", "f"}, values)
+		assert.NoError(t, p.Err())
+
+		assert.Equal(t, "", tokens[0].Start.Path)
+		assert.Equal(t, filepath.Join(dir, "sub", "b.inc"), tokens[1].Start.Path)
+		assert.Equal(t, 1, tokens[1].Start.Line)
+		assert.Equal(t, filepath.Join(dir, "sub", "b.inc"), tokens[2].Start.Path)
+		assert.Equal(t, 2, tokens[2].Start.Line)
+		assert.Equal(t, filepath.Join(dir, "sub", "d.inc"), tokens[3].Start.Path)
+		assert.Equal(t, filepath.Join(dir, "c.inc"), tokens[4].Start.Path)
+		assert.Equal(t, "", tokens[5].Start.Path)
+	})
+
+	t.Run("include search path", func(t *testing.T) {
+		assert.Equal(t, []string{"x", "e", "y"}, tokenValues(newPreprocessor("x {$I e.inc} y")))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		p := newPreprocessor("x {$I unknown.inc} y")
+		assert.Equal(t, []string{"x", "y"}, tokenValues(p))
+		if assert.Error(t, p.Err()) {
+			assert.Contains(t, p.Err().Error(), "failed to include unknown.inc at 1:3")
+			assert.Contains(t, p.Err().Error(), "include file unknown.inc not found")
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		p := newPreprocessor("x {$I self.inc} y")
+		assert.Equal(t, []string{"x", "s", "y"}, tokenValues(p))
+		if assert.Error(t, p.Err()) {
+			assert.Contains(t, p.Err().Error(), "recursive include of")
+		}
+	})
+
+	t.Run("in inactive block", func(t *testing.T) {
+		p := newPreprocessor("x {$IFDEF NONE} {$I unknown.inc} {$ENDIF} y")
+		assert.Equal(t, []string{"x", "y"}, tokenValues(p))
+		assert.NoError(t, p.Err())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		p := newPreprocessor("x {$I sub/b.inc} y")
+		assert.Equal(t, "x", p.GetNext().RawString())
+		assert.Equal(t, "b1", p.GetNext().RawString())
+		clone := p.Clone()
+		assert.Equal(t, []string{"b2", "d", "y"}, tokenValues(p))
+		assert.Equal(t, []string{"b2", "d", "y"}, tokenValues(clone))
+	})
+}
+
+func TestPreprocessorDirectives(t *testing.T) {
+	text := "{$R *.res} {$IFDEF X} {$WARN SYMBOL_DEPRECATED OFF} {$IFDEF Y} {$H-} {$ENDIF} {$ELSE} {$H+} {$ENDIF} a"
+	runes := []rune(text)
+	p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment), nil)
+	a := p.GetNext()
+	assert.Equal(t, "a", a.RawString())
+	assert.Equal(t, token.EOF, p.GetNext().Type)
+	names := []string{}
+	for i, d := range p.Directives() {
+		names = append(names, d.Token.RawString())
+		if assert.Len(t, a.LeadingDirectives, len(p.Directives())) {
+			assert.Same(t, d.Token, a.LeadingDirectives[i])
+		}
+	}
+	assert.Equal(t, []string{"{$R *.res}", "{$IFDEF X}", "{$ELSE}", "{$H+}", "{$ENDIF}"}, names)
+}
+
+func TestPreprocessorLossless(t *testing.T) {
+	text := "unit Foo; {$DEFINE A}\n{$IFDEF B}\n  x; // inactive\n{$ELSE}\n  y; { active }\n{$ENDIF}\nend.\n"
+	runes := []rune(text)
+	p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment|token.LoadTrivia), nil)
+	values := []string{}
+	var b strings.Builder
+	for {
+		tk := p.GetNext()
+		b.WriteString(tk.FullString())
+		if tk.Type == token.EOF {
+			break
+		}
+		values = append(values, tk.RawString())
+	}
+	assert.Equal(t, []string{"unit", "Foo", ";", "y", ";", "end", "."}, values)
+	assert.Equal(t, text, b.String())
+}
+
+func TestPreprocessorLosslessInclude(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.pas":  "unit Foo; // main\n{$I sub/b.inc} { after b }\n{$I empty.inc}\n{$IFDEF X}\n{$I c.inc}\n{$ENDIF}\nend.\n",
+		"sub/b.inc": "  b1; // b\n{$I d.inc}\n  b2; { end of b }\n\n",
+		"sub/d.inc": "{ d } d;",
+		"c.inc":     "c;\n",
+		"empty.inc": "{ no tokens }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if !assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755)) {
+			return
+		}
+		if !assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644)) {
+			return
+		}
+	}
+
+	readAll := func(p *Preprocessor) []*token.Token {
+		r := []*token.Token{}
+		for {
+			tk := p.GetNext()
+			r = append(r, tk)
+			if tk.Type == token.EOF {
+				return r
+			}
+		}
+	}
+	assertTexts := func(t *testing.T, tokens []*token.Token) {
+		assert.Equal(t, files["main.pas"], token.SourceText(tokens))
+		for _, name := range []string{"sub/b.inc", "sub/d.inc"} {
+			var b strings.Builder
+			for _, tk := range tokens {
+				if tk.Start.Path == filepath.Join(dir, name) {
+					assert.True(t, tk.Included)
+					b.WriteString(tk.FullString())
+				}
+			}
+			assert.Equal(t, files[name], b.String(), name)
+		}
+	}
+
+	runes := []rune(files["main.pas"])
+	p := NewPreprocessor(token.NewTokenizer(&runes, token.LoadComment|token.LoadTrivia), nil)
+	p.Path = filepath.Join(dir, "main.pas")
+
+	head := []*token.Token{p.GetNext(), p.GetNext(), p.GetNext(), p.GetNext()}
+	clone := p.Clone()
+	tokens := append(head, readAll(p)...)
+
+	values := []string{}
+	for _, tk := range tokens {
+		values = append(values, tk.RawString())
+	}
+	assert.Equal(t, []string{"unit", "Foo", ";", "b1", ";", "d", ";", "b2", ";", "end", ".", ""}, values)
+	assertTexts(t, tokens)
+
+	t.Run("rollback", func(t *testing.T) {
+		assertTexts(t, append(head, readAll(clone)...))
+	})
+}
+
+func tokenValues(p *Preprocessor) []string {
+	r := []string{}
+	for {
+		t := p.GetNext()
+		if t == nil || t.Type == token.EOF {
+			break
+		}
+		r = append(r, t.RawString())
+	}
+	return r
+}
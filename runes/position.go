@@ -6,6 +6,9 @@ type Position struct {
 	Line  int
 	Col   int
 	Index int
+	// Path is the path of the file which is included by another file.
+	// It is empty for the text given to the parser directly.
+	Path string
 }
 
 func NewPosition() *Position {
@@ -0,0 +1,98 @@
+package token
+
+import (
+	"strings"
+
+	"github.com/akm/tparser/runes"
+)
+
+// asmProcessors are used instead of processors in assembler mode.
+var asmProcessors = []func(*runes.Cursor) *Token{
+	ProcessEof,
+	ProcessComment,
+	ProcessString,
+	ProcessAsmString,
+	ProcessAsmNumeral,
+	ProcessAsmWord,
+	ProcessSingleSpecialSymbol,
+	ProcessSpace,
+}
+
+// ProcessAsmWord reads a word in asm blocks. Words can start with '@' like
+// local labels such as @@loop. All words except END are Identifier because
+// reserved words in Object Pascal such as AND, OR or SHL are instructions
+// or operators in asm blocks.
+func ProcessAsmWord(c *runes.Cursor) *Token {
+	r := c.Current()
+	if r == '@' {
+		i := 1
+		for c.Seek(i) == '@' {
+			i++
+		}
+		if !runes.IsWordHead(c.Seek(i)) {
+			return nil
+		}
+	} else if !runes.IsWordHead(r) {
+		return nil
+	}
+	start := c.Position.Clone()
+	for {
+		r := c.Next()
+		if !runes.IsWord(r) && r != '@' {
+			break
+		}
+	}
+	t := NewToken(Identifier, c.Text, start, c.Position.Clone())
+	if strings.ToUpper(t.RawString()) == "END" {
+		t.Type = ReservedWord
+	}
+	return t
+}
+
+// ProcessAsmNumeral reads a number in asm blocks. Numbers can be written
+// with a radix suffix such as 0FFH, 1010B or 777O, or with '$' such as $FF.
+// Unlike ProcessNumeral, '-' is not a part of numbers.
+func ProcessAsmNumeral(c *runes.Cursor) *Token {
+	r := c.Current()
+	if r == '$' {
+		if !isHexDigit(c.Seek(1)) {
+			return nil
+		}
+		start := c.Position.Clone()
+		for isHexDigit(c.Next()) {
+		}
+		return NewToken(NumeralInt, c.Text, start, c.Position.Clone())
+	}
+	if !runes.IsDigit(r) {
+		return nil
+	}
+	start := c.Position.Clone()
+	for runes.IsWord(c.Next()) {
+	}
+	if c.Current() == '.' && runes.IsDigit(c.Seek(1)) {
+		c.Next()
+		for runes.IsDigit(c.Next()) {
+		}
+		return NewToken(NumeralReal, c.Text, start, c.Position.Clone())
+	}
+	return NewToken(NumeralInt, c.Text, start, c.Position.Clone())
+}
+
+// ProcessAsmString reads a string quoted by '"' which is allowed in asm blocks.
+func ProcessAsmString(c *runes.Cursor) *Token {
+	if c.Current() != '"' {
+		return nil
+	}
+	start := c.Position.Clone()
+	for {
+		if r := c.Next(); r == '"' || r == runes.CursorEOF {
+			break
+		}
+	}
+	c.Next()
+	return NewToken(CharacterString, c.Text, start, c.Position.Clone())
+}
+
+func isHexDigit(r rune) bool {
+	return runes.IsDigit(r) || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+}
@@ -0,0 +1,73 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsmMode(t *testing.T) {
+	text := []rune(`@@loop: AND EAX, 0FFH { end }
+  MOV [EBP-4], $7F
+  DB "abc", 1.5
+end`)
+	x := token.NewTokenizer(&text, 0)
+	x.SetAsmMode(true)
+	actual := TestTokens{}
+	for {
+		tok := x.GetNext()
+		if tok == nil || tok.Type == token.EOF {
+			break
+		}
+		actual = append(actual, &TestToken{Type: tok.Type, Content: tok.RawString()})
+	}
+	assert.Equal(t, TestTokens{
+		{Type: token.Identifier, Content: "@@loop"},
+		{Type: token.SpecialSymbol, Content: ":"},
+		{Type: token.Identifier, Content: "AND"},
+		{Type: token.Identifier, Content: "EAX"},
+		{Type: token.SpecialSymbol, Content: ","},
+		{Type: token.NumeralInt, Content: "0FFH"},
+		{Type: token.Identifier, Content: "MOV"},
+		{Type: token.SpecialSymbol, Content: "["},
+		{Type: token.Identifier, Content: "EBP"},
+		{Type: token.SpecialSymbol, Content: "-"},
+		{Type: token.NumeralInt, Content: "4"},
+		{Type: token.SpecialSymbol, Content: "]"},
+		{Type: token.SpecialSymbol, Content: ","},
+		{Type: token.NumeralInt, Content: "$7F"},
+		{Type: token.Identifier, Content: "DB"},
+		{Type: token.CharacterString, Content: `"abc"`},
+		{Type: token.SpecialSymbol, Content: ","},
+		{Type: token.NumeralReal, Content: "1.5"},
+		{Type: token.ReservedWord, Content: "end"},
+	}, actual)
+}
+
+func TestAsmModeBraceCommentsDoNotNest(t *testing.T) {
+	text := []rune(`DB 12 (* {{Delphi}} style string *)
+  { a { b } CMP AL, '}'
+end`)
+	x := token.NewTokenizer(&text, token.LoadComment)
+	x.SetAsmMode(true)
+	actual := TestTokens{}
+	for {
+		tok := x.GetNext()
+		if tok == nil || tok.Type == token.EOF {
+			break
+		}
+		actual = append(actual, &TestToken{Type: tok.Type, Content: tok.RawString()})
+	}
+	assert.Equal(t, TestTokens{
+		{Type: token.Identifier, Content: "DB"},
+		{Type: token.NumeralInt, Content: "12"},
+		{Type: token.Comment, Content: "(* {{Delphi}} style string *)"},
+		{Type: token.Comment, Content: "{ a { b }"},
+		{Type: token.Identifier, Content: "CMP"},
+		{Type: token.Identifier, Content: "AL"},
+		{Type: token.SpecialSymbol, Content: ","},
+		{Type: token.CharacterString, Content: "'}'"},
+		{Type: token.ReservedWord, Content: "end"},
+	}, actual)
+}
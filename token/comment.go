@@ -4,9 +4,12 @@ import (
 	"github.com/akm/tparser/runes"
 )
 
+// ProcessComment reads a comment. A comment starting with $ such as {$R+} or
+// (*$R+*) is read as CompilerDirective.
 func ProcessComment(c *runes.Cursor) *Token {
 	switch c.Current() {
 	case '{':
+		directive := c.Seek(1) == '$'
 		start := c.Position.Clone()
 		for {
 			if r := c.Next(); r == '}' || r == runes.CursorEOF {
@@ -14,7 +17,7 @@ func ProcessComment(c *runes.Cursor) *Token {
 			}
 		}
 		c.Next()
-		return NewToken(Comment, c.Text, start, c.Position.Clone())
+		return newCommentToken(directive, c.Text, start, c.Position.Clone())
 	case '/':
 		if c.Seek(1) == '/' {
 			start := c.Position.Clone()
@@ -29,6 +32,7 @@ func ProcessComment(c *runes.Cursor) *Token {
 		return nil
 	case '(':
 		if c.Seek(1) == '*' {
+			directive := c.Seek(2) == '$'
 			start := c.Position.Clone()
 			for {
 				r := c.Next()
@@ -41,9 +45,18 @@ func ProcessComment(c *runes.Cursor) *Token {
 				}
 			}
 			c.Next()
-			return NewToken(Comment, c.Text, start, c.Position.Clone())
+			return newCommentToken(directive, c.Text, start, c.Position.Clone())
 		}
 		return nil
 	}
 	return nil
 }
+
+func newCommentToken(directive bool, text *[]rune, start, end *runes.Position) *Token {
+	if !directive {
+		return NewToken(Comment, text, start, end)
+	}
+	r := NewToken(CompilerDirective, text, start, end)
+	r.CompilerDirective = ParseDirectiveContent(r.RawString())
+	return r
+}
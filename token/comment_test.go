@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestComment(t *testing.T) {
@@ -23,6 +24,53 @@ func TestComment(t *testing.T) {
 			text:   `(* comment *)`,
 			tokens: TestTokens{{Type: token.Comment, Content: "(* comment *)"}},
 		},
+		{
+			flags:  token.LoadComment,
+			text:   `{$R *.dfm}`,
+			tokens: TestTokens{{Type: token.CompilerDirective, Content: "{$R *.dfm}"}},
+		},
+		{
+			flags:  token.LoadComment,
+			text:   `(*$H+*)`,
+			tokens: TestTokens{{Type: token.CompilerDirective, Content: "(*$H+*)"}},
+		},
+		{
+			flags:  token.LoadComment,
+			text:   `{ $R not a directive }`,
+			tokens: TestTokens{{Type: token.Comment, Content: "{ $R not a directive }"}},
+		},
 	}
 	patterns.check(t)
 }
+
+func TestCompilerDirective(t *testing.T) {
+	type pattern struct {
+		text     string
+		expected *token.DirectiveContent
+	}
+	patterns := []pattern{
+		{"{$R *.dfm}", &token.DirectiveContent{Name: "R", Argument: "*.dfm", Args: []string{"*.dfm"}}},
+		{"{$H+}", &token.DirectiveContent{Name: "H", Argument: "+", Args: []string{"+"}}},
+		{"{$R+,Q-}", &token.DirectiveContent{Name: "R", Argument: "+,Q-", Args: []string{"+", "Q-"}}},
+		{
+			"{$WARN SYMBOL_DEPRECATED OFF}",
+			&token.DirectiveContent{Name: "WARN", Argument: "SYMBOL_DEPRECATED OFF", Args: []string{"SYMBOL_DEPRECATED", "OFF"}},
+		},
+		{"(*$mode delphi*)", &token.DirectiveContent{Name: "MODE", Argument: "delphi", Args: []string{"delphi"}}},
+		{
+			"{$R 'My Form.res' 'My Form.rc'}",
+			&token.DirectiveContent{Name: "R", Argument: "'My Form.res' 'My Form.rc'", Args: []string{"My Form.res", "My Form.rc"}},
+		},
+		{"{$ENDIF}", &token.DirectiveContent{Name: "ENDIF"}},
+	}
+	for _, ptn := range patterns {
+		t.Run(ptn.text, func(t *testing.T) {
+			runes := []rune(ptn.text)
+			tk := token.NewTokenizer(&runes, token.LoadComment).GetNext()
+			assert.Equal(t, token.CompilerDirective, tk.Type)
+			assert.Equal(t, ptn.expected, tk.CompilerDirective)
+		})
+	}
+
+	assert.Nil(t, token.ParseDirectiveContent("{ comment }"))
+}
@@ -0,0 +1,83 @@
+package token
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DirectiveContent is the content of a compiler directive such as
+// {$R *.dfm}, {$H+} or {$WARN SYMBOL_DEPRECATED OFF}.
+type DirectiveContent struct {
+	Name     string   // upper case name such as "R", "H" or "WARN"
+	Argument string   // the rest of the directive such as "*.dfm", "+" or "SYMBOL_DEPRECATED OFF"
+	Args     []string // Argument split by spaces and commas
+}
+
+// ParseDirectiveContent returns the content of the compiler directive s
+// which starts with {$ or (*$. It returns nil if s is not a compiler directive.
+//
+// Args are split by spaces and commas, so {$R+,Q-} has "+" and "Q-".
+// A quoted argument such as 'My File.inc' is an argument without the quotes.
+func ParseDirectiveContent(s string) *DirectiveContent {
+	var body string
+	switch {
+	case strings.HasPrefix(s, "{$"):
+		body = strings.TrimSuffix(s[2:], "}")
+	case strings.HasPrefix(s, "(*$"):
+		body = strings.TrimSuffix(s[3:], "*)")
+	default:
+		return nil
+	}
+
+	nameEnd := strings.IndexFunc(body, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+	})
+	if nameEnd < 0 {
+		nameEnd = len(body)
+	}
+	argument := strings.TrimSpace(body[nameEnd:])
+	return &DirectiveContent{
+		Name:     strings.ToUpper(body[:nameEnd]),
+		Argument: argument,
+		Args:     splitDirectiveArgs(argument),
+	}
+}
+
+func splitDirectiveArgs(s string) []string {
+	var res []string
+	var b strings.Builder
+	inArg := false
+	flush := func() {
+		if inArg {
+			res = append(res, b.String())
+			b.Reset()
+			inArg = false
+		}
+	}
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == ',' || unicode.IsSpace(r):
+			flush()
+		case r == '\'':
+			inArg = true
+			for i++; i < len(rs); i++ {
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						b.WriteRune('\'')
+						i++
+						continue
+					}
+					break
+				}
+				b.WriteRune(rs[i])
+			}
+		default:
+			inArg = true
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return res
+}
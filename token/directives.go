@@ -82,7 +82,7 @@ var directives = ext.Strings{
 	"REGISTER",
 	"REINTRODUCE",
 	// "REQUIRES", // Used in RequiresClause
-	// "RESIDENT", // Not found in Grammer
+	"RESIDENT", // Used in ExportsItem
 	"SAFECALL",
 	"STDCALL",
 	// "STORED", // Used in PropertySpecifiers
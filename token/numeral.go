@@ -1,16 +1,27 @@
 package token
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/akm/tparser/runes"
+	"github.com/pkg/errors"
 )
 
+// ProcessNumeral reads a decimal, hexadecimal ($FF) or binary (%1010) integer
+// or a real number such as 1.5E-3. Digits can be separated by '_' like 1_000_000.
 func ProcessNumeral(c *runes.Cursor) *Token {
-	if runes.IsDigit(c.Current()) || (runes.IsUnaryOp(c.Current()) && runes.IsDigit(c.Seek(1))) {
+	switch r := c.Current(); {
+	case r == '$' && isHexDigit(c.Seek(1)):
+		return readRadixNumeral(c, isHexDigit)
+	case r == '%' && isBinaryDigit(c.Seek(1)):
+		return readRadixNumeral(c, isBinaryDigit)
+	case runes.IsDigit(r) || (runes.IsUnaryOp(r) && runes.IsDigit(c.Seek(1))):
 		start := c.Position.Clone()
 		tokenType := NumeralInt
 		for {
 			r := c.Next()
-			if runes.IsDigit(r) {
+			if runes.IsDigit(r) || r == '_' {
 				// OK
 			} else if r == runes.CursorEOF {
 				break
@@ -27,7 +38,83 @@ func ProcessNumeral(c *runes.Cursor) *Token {
 				break
 			}
 		}
+		if r := c.Current(); r == 'E' || r == 'e' {
+			if runes.IsDigit(c.Seek(1)) || (isSign(c.Seek(1)) && runes.IsDigit(c.Seek(2))) {
+				tokenType = NumeralReal
+				c.Next()
+				for runes.IsDigit(c.Next()) {
+				}
+			}
+		}
 		return NewToken(tokenType, c.Text, start, c.Position.Clone())
 	}
 	return nil
 }
+
+func readRadixNumeral(c *runes.Cursor, isDigit func(rune) bool) *Token {
+	start := c.Position.Clone()
+	for {
+		if r := c.Next(); !isDigit(r) && r != '_' {
+			break
+		}
+	}
+	return NewToken(NumeralInt, c.Text, start, c.Position.Clone())
+}
+
+func isSign(r rune) bool {
+	return r == '+' || r == '-'
+}
+
+func isBinaryDigit(r rune) bool {
+	return r == '0' || r == '1'
+}
+
+// ParseNumeral returns the radix and the value of the numeral read by ProcessNumeral.
+// i is the value of integer numerals, and f is the value as a real number.
+// Integers beyond Int64 such as $FFFFFFFFFFFFFFFF are wrapped around like
+// UInt64 values cast to Int64, and decimal integers beyond UInt64 are
+// treated as real numbers.
+func ParseNumeral(s string) (radix int, i int64, f float64, err error) {
+	body := strings.ReplaceAll(s, "_", "")
+	neg := false
+	if body != "" && isSign(rune(body[0])) {
+		neg = body[0] == '-'
+		body = body[1:]
+	}
+	radix = 10
+	if body != "" {
+		switch body[0] {
+		case '$':
+			radix, body = 16, body[1:]
+		case '%':
+			radix, body = 2, body[1:]
+		}
+	}
+
+	parseFloat := func() (int, int64, float64, error) {
+		f, err := strconv.ParseFloat(body, 64)
+		if err != nil {
+			return 0, 0, 0, errors.Wrapf(err, "invalid numeral %s", s)
+		}
+		if neg {
+			f = -f
+		}
+		return 10, 0, f, nil
+	}
+
+	if radix == 10 && strings.ContainsAny(body, ".eE") {
+		return parseFloat()
+	}
+	u, err := strconv.ParseUint(body, radix, 64)
+	if err != nil {
+		if radix == 10 && errors.Is(err, strconv.ErrRange) {
+			return parseFloat()
+		}
+		return 0, 0, 0, errors.Wrapf(err, "invalid numeral %s", s)
+	}
+	i, f = int64(u), float64(u)
+	if neg {
+		i, f = -i, -f
+	}
+	return radix, i, f, nil
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNumerical(t *testing.T) {
@@ -17,4 +18,52 @@ func TestNumerical(t *testing.T) {
 	run(`123.456`, &TestToken{Type: token.NumeralReal, Content: "123.456"})
 	run(`-2`, &TestToken{Type: token.NumeralInt, Content: "-2"})
 	run(`-9.9876`, &TestToken{Type: token.NumeralReal, Content: "-9.9876"})
+	run(`$FF00`, &TestToken{Type: token.NumeralInt, Content: "$FF00"})
+	run(`$ff_ff`, &TestToken{Type: token.NumeralInt, Content: "$ff_ff"})
+	run(`%1010`, &TestToken{Type: token.NumeralInt, Content: "%1010"})
+	run(`1_000_000`, &TestToken{Type: token.NumeralInt, Content: "1_000_000"})
+	run(`1.5E-3`, &TestToken{Type: token.NumeralReal, Content: "1.5E-3"})
+	run(`1e10`, &TestToken{Type: token.NumeralReal, Content: "1e10"})
+	run(`2.5e+2`, &TestToken{Type: token.NumeralReal, Content: "2.5e+2"})
+
+	(&TestPattern{
+		text: `1..10`,
+		tokens: TestTokens{
+			{Type: token.NumeralInt, Content: "1"},
+			{Type: token.SpecialSymbol, Content: ".."},
+			{Type: token.NumeralInt, Content: "10"},
+		},
+	}).check(t)
+}
+
+func TestParseNumeral(t *testing.T) {
+	patterns := []struct {
+		text  string
+		radix int
+		i     int64
+		f     float64
+	}{
+		{"123", 10, 123, 123},
+		{"-2", 10, -2, -2},
+		{"1_000_000", 10, 1000000, 1000000},
+		{"$FF00", 16, 0xFF00, 0xFF00},
+		{"$ff_ff", 16, 0xFFFF, 0xFFFF},
+		{"%1010", 2, 10, 10},
+		{"$FFFFFFFFFFFFFFFF", 16, -1, 18446744073709551615},
+		{"123.456", 10, 0, 123.456},
+		{"1.5E-3", 10, 0, 0.0015},
+		{"1e10", 10, 0, 1e10},
+		{"-9.9876", 10, 0, -9.9876},
+		{"100000000000000000000", 10, 0, 1e20},
+	}
+	for _, ptn := range patterns {
+		t.Run(ptn.text, func(t *testing.T) {
+			radix, i, f, err := token.ParseNumeral(ptn.text)
+			if assert.NoError(t, err) {
+				assert.Equal(t, ptn.radix, radix)
+				assert.Equal(t, ptn.i, i)
+				assert.Equal(t, ptn.f, f)
+			}
+		})
+	}
 }
@@ -23,3 +23,17 @@ func TestPredicatorl(t *testing.T) {
 	)
 	assert.True(t, p0.Predicate(t0))
 }
+
+func TestDirectivesAreIdentifiers(t *testing.T) {
+	words := append(directives.Slice(), portabilityDirectives.Slice()...)
+	for _, w := range words {
+		// LIBRARY is a reserved word and a portability directive.
+		if isReservedWord(w) {
+			continue
+		}
+		text := []rune(w)
+		tk := NewTokenizer(&text, 0).GetNext()
+		assert.Equal(t, Identifier, tk.Type, w)
+		assert.True(t, tk.Is(Identifier), w)
+	}
+}
@@ -15,3 +15,21 @@ func ProcessSpace(c *runes.Cursor) *Token {
 	}
 	return nil
 }
+
+// ProcessLineSpace reads spaces like ProcessSpace, but it stops after a line break
+// so that the spaces at the head of the next line belong to the next token.
+func ProcessLineSpace(c *runes.Cursor) *Token {
+	r := c.Current()
+	if !unicode.IsSpace(r) {
+		return nil
+	}
+	start := c.Position.Clone()
+	for unicode.IsSpace(r) {
+		next := c.Next()
+		if r == '\n' {
+			break
+		}
+		r = next
+	}
+	return NewToken(Space, c.Text, start, c.Position.Clone())
+}
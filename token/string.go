@@ -1,23 +1,198 @@
 package token
 
 import (
+	"strconv"
+	"strings"
+	"unicode"
+
 	"github.com/akm/tparser/runes"
 )
 
+// ProcessString reads a character string which consists of quoted strings
+// and control strings without spaces between them such as 'Line1'#13#10'Line2'.
+//
+// In quoted strings, a single quote is written twice.
+// Control strings are written as #nn, #$hh or ^C.
+// ^C can't be distinguished from pointer types or dereferences by itself, so
+// it starts a character string only when it precedes the other parts of the
+// character string, or when it follows ':=', '(', ',', '[' or an operator
+// where an operand is expected. The others such as `CR = ^M` are read as '^'
+// and C, and joined by JoinControlString.
 func ProcessString(c *runes.Cursor) *Token {
+	if !isStringHead(c) {
+		return nil
+	}
+	start := c.Position.Clone()
+	for readStringPart(c) {
+	}
+	return NewToken(CharacterString, c.Text, start, c.Position.Clone())
+}
+
+func isStringHead(c *runes.Cursor) bool {
+	switch c.Current() {
+	case '\'':
+		return true
+	case '#':
+		return isControlCodeHead(c.Seek(1))
+	case '^':
+		if !isControlLetter(c.Seek(1)) || isOperandEnd(previousRune(c)) {
+			return false
+		}
+		switch c.Seek(2) {
+		case '\'', '#', '^':
+			return true
+		}
+		return isOperandExpected(c)
+	}
+	return false
+}
+
+// previousRune returns the rune before the cursor skipping spaces.
+// It returns runes.CursorEOF at the beginning of the text.
+func previousRune(c *runes.Cursor) rune {
+	for i := c.Position.Index - 1; i >= 0; i-- {
+		if r := (*c.Text)[i]; !unicode.IsSpace(r) {
+			return r
+		}
+	}
+	return runes.CursorEOF
+}
+
+// isOperandEnd returns true if r can be the end of an operand like P of P^
+// or ] of A[0]^, which is followed by '^' for dereference.
+func isOperandEnd(r rune) bool {
+	return runes.IsWord(r) || r == ')' || r == ']' || r == '^' || r == '\''
+}
+
+// isOperandExpected returns true if the token before the cursor is ':=', '(',
+// ',', '[' or an operator, which is followed by an operand but not by a type.
+func isOperandExpected(c *runes.Cursor) bool {
+	switch previousRune(c) {
+	case '(', ',', '[', '+', '-', '*', '/', '<', '>':
+		return true
+	case '=':
+		// := but not = of type declarations such as `PT = ^T`
+		for i := c.Position.Index - 1; i > 0; i-- {
+			if r := (*c.Text)[i]; r == '=' {
+				return (*c.Text)[i-1] == ':'
+			}
+		}
+	}
+	return false
+}
+
+// readStringPart reads a quoted string or a control string and returns true.
+// It returns false if the current rune doesn't start any of them.
+func readStringPart(c *runes.Cursor) bool {
 	switch c.Current() {
 	case '\'':
-		start := c.Position.Clone()
-		var last rune
 		for {
 			r := c.Next()
-			if last != '\\' && r == '\'' {
-				break
+			if r == '\'' {
+				if c.Seek(1) == '\'' {
+					c.Next()
+					continue
+				}
+				c.Next()
+				return true
+			}
+			// Quoted strings can't continue over lines.
+			if r == runes.CursorEOF || r == '\r' || r == '\n' {
+				return true
 			}
-			last = r
 		}
+	case '#':
+		if !isControlCodeHead(c.Seek(1)) {
+			return false
+		}
+		if c.Next() == '$' {
+			for isHexDigit(c.Next()) {
+			}
+		} else {
+			for runes.IsDigit(c.Next()) {
+			}
+		}
+		return true
+	case '^':
+		if !isControlLetter(c.Seek(1)) {
+			return false
+		}
+		c.Next()
 		c.Next()
-		return NewToken(CharacterString, c.Text, start, c.Position.Clone())
+		return true
+	}
+	return false
+}
+
+func isControlCodeHead(r rune) bool {
+	return r == '$' || runes.IsDigit(r)
+}
+
+func isControlLetter(r rune) bool {
+	return unicode.IsLetter(r) || ('@' <= r && r <= '_')
+}
+
+// JoinControlString returns the control string such as ^M which consists of
+// caret '^' and the following token next. It returns nil if next doesn't
+// follow caret immediately or next is not a control letter.
+func JoinControlString(caret, next *Token) *Token {
+	if caret == nil || next == nil || !caret.Is(Symbol('^')) {
+		return nil
+	}
+	if next.text != caret.text || next.Start.Index != caret.End.Index {
+		return nil
+	}
+	if r := next.Raw(); len(r) != 1 || !isControlLetter(r[0]) {
+		return nil
+	}
+	return NewToken(CharacterString, caret.text, caret.Start.Clone(), next.End.Clone())
+}
+
+// DecodeString returns the text which the character string represents.
+// For example, 'Line1'#13#10 is decoded to "Line1\r\n".
+func DecodeString(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i := 0; i < len(rs); {
+		switch rs[i] {
+		case '\'':
+			i++
+			for i < len(rs) {
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						b.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteRune(rs[i])
+				i++
+			}
+		case '#':
+			i++
+			base, isDigit := 10, runes.IsDigit
+			if i < len(rs) && rs[i] == '$' {
+				base, isDigit = 16, isHexDigit
+				i++
+			}
+			j := i
+			for j < len(rs) && isDigit(rs[j]) {
+				j++
+			}
+			if code, err := strconv.ParseInt(string(rs[i:j]), base, 32); err == nil {
+				b.WriteRune(rune(code))
+			}
+			i = j
+		case '^':
+			if i+1 < len(rs) {
+				b.WriteRune(unicode.ToUpper(rs[i+1]) ^ 0x40)
+			}
+			i += 2
+		default:
+			i++
+		}
 	}
-	return nil
+	return b.String()
 }
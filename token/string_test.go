@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestString(t *testing.T) {
@@ -17,9 +18,114 @@ func TestString(t *testing.T) {
 			tokens: TestTokens{{Type: token.CharacterString, Content: "'string1'"}},
 		},
 		{
-			text:   `'with \'single quotes\''`,
-			tokens: TestTokens{{Type: token.CharacterString, Content: "'with \\'single quotes\\''"}},
+			text:   `'with ''single quotes'''`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "'with ''single quotes'''"}},
+		},
+		{
+			text:   `'C:\'`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: `'C:\'`}},
+		},
+		{
+			text:   `#13#10`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "#13#10"}},
+		},
+		{
+			text:   `#$0D#$0a`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "#$0D#$0a"}},
+		},
+		{
+			text:   `'Line1'#13#10'Line2'^M^J`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "'Line1'#13#10'Line2'^M^J"}},
+		},
+		{
+			text:   `^M'Line1'`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "^M'Line1'"}},
+		},
+		{
+			text: `'Line1' #13`,
+			tokens: TestTokens{
+				{Type: token.CharacterString, Content: "'Line1'"},
+				{Type: token.CharacterString, Content: "#13"},
+			},
+		},
+		{
+			text: `S := ^M;`,
+			tokens: TestTokens{
+				{Type: token.Identifier, Content: "S"},
+				{Type: token.SpecialSymbol, Content: ":="},
+				{Type: token.CharacterString, Content: "^M"},
+				{Type: token.SpecialSymbol, Content: ";"},
+			},
+		},
+		{
+			text: `F(^A, ^[)`,
+			tokens: TestTokens{
+				{Type: token.Identifier, Content: "F"},
+				{Type: token.SpecialSymbol, Content: "("},
+				{Type: token.CharacterString, Content: "^A"},
+				{Type: token.SpecialSymbol, Content: ","},
+				{Type: token.CharacterString, Content: "^["},
+				{Type: token.SpecialSymbol, Content: ")"},
+			},
+		},
+		{
+			text:   `^['Line1'`,
+			tokens: TestTokens{{Type: token.CharacterString, Content: "^['Line1'"}},
+		},
+		{
+			text: `P^['a']`,
+			tokens: TestTokens{
+				{Type: token.Identifier, Content: "P"},
+				{Type: token.SpecialSymbol, Content: "^"},
+				{Type: token.SpecialSymbol, Content: "["},
+				{Type: token.CharacterString, Content: "'a'"},
+				{Type: token.SpecialSymbol, Content: "]"},
+			},
+		},
+		{
+			text: `P: ^T;`,
+			tokens: TestTokens{
+				{Type: token.Identifier, Content: "P"},
+				{Type: token.SpecialSymbol, Content: ":"},
+				{Type: token.SpecialSymbol, Content: "^"},
+				{Type: token.Identifier, Content: "T"},
+				{Type: token.SpecialSymbol, Content: ";"},
+			},
+		},
+		{
+			text: `P = ^M;`,
+			tokens: TestTokens{
+				{Type: token.Identifier, Content: "P"},
+				{Type: token.SpecialSymbol, Content: "="},
+				{Type: token.SpecialSymbol, Content: "^"},
+				{Type: token.Identifier, Content: "M"},
+				{Type: token.SpecialSymbol, Content: ";"},
+			},
 		},
 	}
 	patterns.check(t)
 }
+
+func TestDecodeString(t *testing.T) {
+	patterns := []struct {
+		text     string
+		expected string
+	}{
+		{`''`, ""},
+		{`'string1'`, "string1"},
+		{`'It''s'`, "It's"},
+		{`''''`, "'"},
+		{`'C:\'`, `C:\`},
+		{`#13#10`, "\r\n"},
+		{`#$0D#$0a`, "\r\n"},
+		{`'Line1'#13#10'Line2'`, "Line1\r\nLine2"},
+		{`'A'^M^j'B'`, "A\r\nB"},
+		{`#$3042'i'`, "あi"},
+		{`^[`, "\x1b"},
+	}
+	for _, ptn := range patterns {
+		t.Run(ptn.text, func(t *testing.T) {
+			assert.Equal(t, ptn.expected, token.DecodeString(ptn.text))
+		})
+	}
+}
@@ -12,6 +12,18 @@ type Token struct {
 	text  *[]rune
 	Start *runes.Position
 	End   *runes.Position
+	// LeadingTrivia and TrailingTrivia are set by Tokenizer with LoadTrivia.
+	LeadingTrivia  Trivia
+	TrailingTrivia Trivia
+	// Included is true if the token is read from a file included by {$I file}.
+	// The token is not a part of the including text, so it must be skipped to
+	// reproduce the text.
+	Included bool
+	// LeadingDirectives are the compiler directives read just before the token
+	// by Preprocessor. They are set with or without LoadTrivia.
+	LeadingDirectives []*Token
+	// CompilerDirective is the content of CompilerDirective token.
+	CompilerDirective *DirectiveContent
 }
 
 func NewToken(typ Type, text *[]rune, start, end *runes.Position) *Token {
@@ -20,10 +32,15 @@ func NewToken(typ Type, text *[]rune, start, end *runes.Position) *Token {
 
 func (t *Token) Clone() *Token {
 	return &Token{
-		Type:  t.Type,
-		text:  t.text,
-		Start: t.Start.Clone(),
-		End:   t.End.Clone(),
+		Type:              t.Type,
+		text:              t.text,
+		Start:             t.Start.Clone(),
+		End:               t.End.Clone(),
+		LeadingTrivia:     t.LeadingTrivia,
+		TrailingTrivia:    t.TrailingTrivia,
+		Included:          t.Included,
+		LeadingDirectives: t.LeadingDirectives,
+		CompilerDirective: t.CompilerDirective,
 	}
 }
 
@@ -1,6 +1,8 @@
 package token
 
 import (
+	"strings"
+
 	"github.com/akm/tparser/runes"
 )
 
@@ -9,12 +11,17 @@ type TokeninzerFlag uint
 const (
 	LoadSpace TokeninzerFlag = 1 << iota
 	LoadComment
+	// LoadTrivia puts spaces and comments to the tokens as their trivia
+	// instead of returning them. Compiler directives are returned as CompilerDirective.
+	LoadTrivia
 )
 
 type Tokenizer struct {
 	*runes.Cursor
 	loadSpace   bool
 	loadComment bool
+	loadTrivia  bool
+	asmMode     bool
 }
 
 func NewTokenizer(text *[]rune, flags TokeninzerFlag) *Tokenizer {
@@ -22,6 +29,7 @@ func NewTokenizer(text *[]rune, flags TokeninzerFlag) *Tokenizer {
 		Cursor:      runes.NewCursor(text),
 		loadSpace:   flags&LoadSpace == LoadSpace,
 		loadComment: flags&LoadComment == LoadComment,
+		loadTrivia:  flags&LoadTrivia == LoadTrivia,
 	}
 }
 
@@ -30,9 +38,31 @@ func (t *Tokenizer) Clone() *Tokenizer {
 		Cursor:      t.Cursor.Clone(),
 		loadSpace:   t.loadSpace,
 		loadComment: t.loadComment,
+		loadTrivia:  t.loadTrivia,
+		asmMode:     t.asmMode,
 	}
 }
 
+// Flags returns the flags given to NewTokenizer.
+func (t *Tokenizer) Flags() TokeninzerFlag {
+	var r TokeninzerFlag
+	if t.loadSpace {
+		r |= LoadSpace
+	}
+	if t.loadComment {
+		r |= LoadComment
+	}
+	if t.loadTrivia {
+		r |= LoadTrivia
+	}
+	return r
+}
+
+// SetAsmMode switches the tokenizer to read the tokens in asm blocks or not.
+func (t *Tokenizer) SetAsmMode(v bool) {
+	t.asmMode = v
+}
+
 var processors = []func(*runes.Cursor) *Token{
 	ProcessEof,
 	ProcessComment,
@@ -44,18 +74,80 @@ var processors = []func(*runes.Cursor) *Token{
 	ProcessSpace,
 }
 
+// triviaProcessors are used with LoadTrivia to split spaces at line breaks.
+var triviaProcessors = []func(*runes.Cursor) *Token{
+	ProcessEof,
+	ProcessComment,
+	ProcessString,
+	ProcessNumeral,
+	ProcessDoubleSpecialSymbol,
+	ProcessSingleSpecialSymbol,
+	ProcessWord,
+	ProcessLineSpace,
+}
+
 func (t *Tokenizer) GetNext() *Token {
-	for _, proc := range processors {
-		token := proc(t.Cursor)
-		if token != nil {
-			if !t.loadSpace && token.Type == Space {
-				return t.GetNext()
-			} else if !t.loadComment && token.Type == Comment {
-				return t.GetNext()
-			} else {
-				return token
-			}
+	if t.loadTrivia {
+		return t.getNextWithTrivia()
+	}
+	token := t.read()
+	if token != nil {
+		if !t.loadSpace && token.Type == Space {
+			return t.GetNext()
+		} else if !t.loadComment && (token.Type == Comment || token.Type == CompilerDirective) {
+			return t.GetNext()
+		}
+	}
+	return token
+}
+
+func (t *Tokenizer) read() *Token {
+	procs := processors
+	if t.asmMode {
+		procs = asmProcessors
+	} else if t.loadTrivia {
+		procs = triviaProcessors
+	}
+	for _, proc := range procs {
+		if token := proc(t.Cursor); token != nil {
+			return token
 		}
 	}
 	return nil
 }
+
+func (t *Tokenizer) getNextWithTrivia() *Token {
+	var leading Trivia
+	for {
+		token := t.read()
+		if token == nil {
+			return nil
+		}
+		if isTrivia(token) {
+			leading = append(leading, token)
+			continue
+		}
+		token.LeadingTrivia = leading
+		if token.Type != EOF {
+			token.TrailingTrivia = t.readTrailingTrivia()
+		}
+		return token
+	}
+}
+
+// readTrailingTrivia reads the trivia up to the end of the current line.
+func (t *Tokenizer) readTrailingTrivia() Trivia {
+	var res Trivia
+	for {
+		cursor := t.Cursor.Clone()
+		token := t.read()
+		if token == nil || !isTrivia(token) {
+			t.Cursor = cursor
+			return res
+		}
+		res = append(res, token)
+		if token.Type == Space && strings.ContainsRune(token.RawString(), '\n') {
+			return res
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package token
+
+import (
+	"strings"
+)
+
+// Trivia is a list of tokens which don't affect the syntax, such as spaces
+// and comments. Tokenizer with LoadTrivia puts them to the tokens.
+//
+// The trailing trivia of a token are the ones up to the end of the line.
+// The other ones are the leading trivia of the next token.
+type Trivia []*Token
+
+func (s Trivia) String() string {
+	var b strings.Builder
+	for _, t := range s {
+		b.WriteString(t.FullString())
+	}
+	return b.String()
+}
+
+// FullString returns the text of the token with its trivia.
+// Concatenating FullString of all the tokens reproduces the original text.
+func (t *Token) FullString() string {
+	return t.LeadingTrivia.String() + t.RawString() + t.TrailingTrivia.String()
+}
+
+// SourceText returns the original text of the tokens read with LoadTrivia.
+// The included tokens are skipped, because the compiler directives including
+// them are kept in the trivia.
+func SourceText(tokens []*Token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		if !t.Included {
+			b.WriteString(t.FullString())
+		}
+	}
+	return b.String()
+}
+
+// Flatten returns the token and its trivia as a list of tokens without trivia.
+func (t *Token) Flatten() Trivia {
+	bare := t.Clone()
+	bare.LeadingTrivia, bare.TrailingTrivia = nil, nil
+	r := make(Trivia, 0, len(t.LeadingTrivia)+1+len(t.TrailingTrivia))
+	r = append(r, t.LeadingTrivia...)
+	r = append(r, bare)
+	return append(r, t.TrailingTrivia...)
+}
+
+// isTrivia returns true if t can be trivia.
+// Compiler directives are not trivia because the preprocessor processes them.
+func isTrivia(t *Token) bool {
+	return t.Type == Space || t.Type == Comment
+}
@@ -0,0 +1,69 @@
+package token_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akm/tparser/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadTrivia(t *testing.T) {
+	text := `unit Foo; // Foo unit
+{ interface section }
+interface
+  (* no declarations *)
+
+implementation
+end.
+`
+	runes := []rune(text)
+	tokenizer := token.NewTokenizer(&runes, token.LoadTrivia)
+	tokens := []*token.Token{}
+	for {
+		tk := tokenizer.GetNext()
+		tokens = append(tokens, tk)
+		if tk.Type == token.EOF {
+			break
+		}
+	}
+
+	t.Run("tokens", func(t *testing.T) {
+		values := []string{}
+		for _, tk := range tokens {
+			values = append(values, tk.RawString())
+		}
+		assert.Equal(t, []string{"unit", "Foo", ";", "interface", "implementation", "end", ".", ""}, values)
+	})
+
+	t.Run("trivia", func(t *testing.T) {
+		assert.Equal(t, "", tokens[0].LeadingTrivia.String())
+		assert.Equal(t, " ", tokens[0].TrailingTrivia.String())
+		assert.Equal(t, " // Foo unit\n", tokens[2].TrailingTrivia.String())
+		assert.Equal(t, "{ interface section }\n", tokens[3].LeadingTrivia.String())
+		assert.Equal(t, "\n", tokens[3].TrailingTrivia.String())
+		assert.Equal(t, "  (* no declarations *)\n\n", tokens[4].LeadingTrivia.String())
+		assert.Equal(t, "\n", tokens[6].TrailingTrivia.String())
+		assert.Equal(t, 3, tokens[3].Start.Line)
+		assert.Equal(t, 6, tokens[4].Start.Line)
+	})
+
+	t.Run("reproduce text", func(t *testing.T) {
+		var b strings.Builder
+		for _, tk := range tokens {
+			b.WriteString(tk.FullString())
+		}
+		assert.Equal(t, text, b.String())
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		flat := tokens[2].Flatten()
+		if assert.Len(t, flat, 4) {
+			assert.Equal(t, ";", flat[0].RawString())
+			assert.Equal(t, token.Space, flat[1].Type)
+			assert.Equal(t, token.Comment, flat[2].Type)
+			assert.Equal(t, "\n", flat[3].RawString())
+			assert.Nil(t, flat[0].TrailingTrivia)
+		}
+	})
+}
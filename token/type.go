@@ -20,6 +20,7 @@ const (
 	NumeralReal
 	Label
 	CharacterString
+	CompilerDirective
 )
 
 var TypeNames = map[Type]string{
@@ -34,6 +35,7 @@ var TypeNames = map[Type]string{
 	NumeralReal:         "real",
 	Label:               "label",
 	CharacterString:     "character string",
+	CompilerDirective:   "compiler directive",
 }
 
 func (t Type) String() string {